@@ -4,6 +4,9 @@ go 1.13
 
 require (
 	github.com/blackducksoftware/horizon v0.0.0-20190625151958-16cafa9109a3
+	github.com/containerd/containerd v1.3.2
+	github.com/deislabs/oras v0.8.1
+	github.com/docker/distribution v2.7.1+incompatible
 	github.com/ghodss/yaml v1.0.0
 	github.com/imdario/mergo v0.3.7
 	github.com/juju/errors v0.0.0-20190806202954-0232dcc7464d
@@ -13,14 +16,18 @@ require (
 	github.com/mitchellh/go-homedir v1.1.0
 	github.com/onsi/ginkgo v1.10.3 // indirect
 	github.com/onsi/gomega v1.7.1 // indirect
+	github.com/opencontainers/go-digest v1.0.0-rc1
+	github.com/opencontainers/image-spec v1.0.1
 	github.com/openshift/api v0.0.0-20200217161739-c99157bc6492
 	github.com/openshift/client-go v0.0.0-20200116152001-92a2713fa240
 	github.com/pkg/errors v0.9.1
+	github.com/pmezard/go-difflib v1.0.0
 	github.com/sirupsen/logrus v1.4.2
 	github.com/spf13/cobra v0.0.5
 	github.com/spf13/pflag v1.0.5
 	github.com/spf13/viper v1.6.2
 	github.com/stretchr/testify v1.4.0
+	golang.org/x/crypto v0.0.0-20200128174031-69ecbb4d6d5d
 	gopkg.in/mgo.v2 v2.0.0-20190816093944-a6b53ec6cb22 // indirect
 	helm.sh/helm/v3 v3.1.1
 	k8s.io/api v0.17.3
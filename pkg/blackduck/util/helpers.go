@@ -23,6 +23,8 @@ package util
 
 import (
 	"fmt"
+	"io"
+	"os"
 	"strings"
 	"time"
 
@@ -124,6 +126,69 @@ func GetHubDBPassword(kubeClient *kubernetes.Clientset, namespace string, name s
 	return userPw, adminPw, nil
 }
 
+// PostgresClientImage is the image of the jobs that run the postgres client tools against a Black Duck database
+const PostgresClientImage = "registry.access.redhat.com/rhscl/postgresql-96-rhel7:1"
+
+// Files of a Black Duck backup
+const (
+	BackupDatabaseFile  = "blackduck.sql"
+	BackupSealKeyFile   = "seal.key"
+	BackupMasterKeyFile = "master.key"
+	BackupValuesFile    = "values.yaml"
+)
+
+// BlackDuckSchema is the schema of the tables of Black Duck in the bds_hub database
+const BlackDuckSchema = "st"
+
+// BlackDuckDatabases are the databases of a Black Duck instance
+var BlackDuckDatabases = []string{"bds_hub", "bds_hub_report", "bdio"}
+
+// BackupMountPath is where the backup PVC is mounted in the backup and restore jobs
+const BackupMountPath = "/backup"
+
+// PostgresAdminPasswordKey is the key of the admin password of the database in the db-creds secret of a Black Duck instance
+const PostgresAdminPasswordKey = "HUB_POSTGRES_ADMIN_PASSWORD_FILE"
+
+// BackupMasterKeyKey is the key of the master key in the secret of a backup job
+const BackupMasterKeyKey = "MASTER_KEY"
+
+// PostgresConnection is how a job connects to the database of a Black Duck instance. The password is read from
+// the PasswordSecretName secret so that it isn't stored in the job
+type PostgresConnection struct {
+	Host               string
+	Port               string
+	User               string
+	PasswordSecretName string
+	SSL                bool
+}
+
+// getSecretEnv returns an environment variable that is read from the key of a secret
+func getSecretEnv(name string, secretName string, key string) corev1.EnvVar {
+	return corev1.EnvVar{
+		Name: name,
+		ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: secretName},
+				Key:                  key,
+			},
+		},
+	}
+}
+
+// getEnvs returns the environment variables of the postgres client tools for the connection
+func (p PostgresConnection) getEnvs() []corev1.EnvVar {
+	envs := []corev1.EnvVar{
+		{Name: "PGHOST", Value: p.Host},
+		{Name: "PGPORT", Value: p.Port},
+		{Name: "PGUSER", Value: p.User},
+		getSecretEnv("PGPASSWORD", p.PasswordSecretName, PostgresAdminPasswordKey),
+	}
+	if p.SSL {
+		envs = append(envs, corev1.EnvVar{Name: "PGSSLMODE", Value: "require"})
+	}
+	return envs
+}
+
 // CloneJob create a Kube job to clone a postgres instance
 func CloneJob(clientset *kubernetes.Clientset, fromNamespace string, from string, toNamespace string, to string, password string) error {
 	command := fmt.Sprintf("pg_dumpall -h %s.%s.svc.cluster.local -U postgres | psql -h %s.%s.svc.cluster.local -U postgres", util.GetResourceName(from, util.BlackDuckName, "postgres"), fromNamespace, util.GetResourceName(to, util.BlackDuckName, "postgres"), toNamespace)
@@ -138,7 +203,7 @@ func CloneJob(clientset *kubernetes.Clientset, fromNamespace string, from string
 					Containers: []corev1.Container{
 						{
 							Name:    "clone",
-							Image:   "registry.access.redhat.com/rhscl/postgresql-96-rhel7:1",
+							Image:   PostgresClientImage,
 							Command: []string{"/bin/bash"},
 							Args: []string{
 								"-c",
@@ -163,23 +228,203 @@ func CloneJob(clientset *kubernetes.Clientset, fromNamespace string, from string
 		return err
 	}
 
-	timeout := time.After(30 * time.Minute)
-	tick := time.Tick(10 * time.Second)
+	return util.WaitForJob(clientset, job.Namespace, job.Name, 30*time.Minute)
+}
 
-	for {
-		select {
-		case <-timeout:
-			return fmt.Errorf("the clone operation timed out")
+// execExitCodeFile is where the command that is run in the pod of a backup job or a backup keys job writes its exit code
+const execExitCodeFile = "/tmp/exec-exit-code"
 
-		case <-tick:
-			job, err = clientset.BatchV1().Jobs(job.Namespace).Get(job.Name, metav1.GetOptions{})
-			if err != nil {
-				return err
-			}
-			if job.Status.Succeeded > 0 {
-				//clientset.BatchV1().Jobs(job.Namespace).Delete(job.Name, &metav1.DeleteOptions{})
-				return nil
-			}
-		}
+// execWaitCommand is the command of a job that waits for a command to be run in its pod and exits with its exit code
+var execWaitCommand = fmt.Sprintf(`until [ -f %[1]s ]; do sleep 1; done; exit "$(cat %[1]s)"`, execExitCodeFile)
+
+// newExecCommand returns a command to run in the pod of a job whose exit code is written to execExitCodeFile
+func newExecCommand(command string) []string {
+	return []string{"/bin/bash", "-c", fmt.Sprintf("(%s); code=$?; echo $code > %s; exit $code", command, execExitCodeFile)}
+}
+
+// BackupDumpCommand is the command that is run in the pod of a backup job without a PVC to stream the dump to its stdout.
+// The container log isn't used for the dump because the kubelet rotates it
+var BackupDumpCommand = newExecCommand("pg_dumpall")
+
+// BackupKeysCommand returns the command that is run in the pod of a backup keys job to write the base64 encoded seal key
+// and master key of the backupDir directory of the PVC to the first two lines of its stdout. The keys aren't written to
+// the container log, so that they don't end up in the logs of the cluster
+func BackupKeysCommand(backupDir string) []string {
+	dir := fmt.Sprintf("%s/%s", BackupMountPath, backupDir)
+	return newExecCommand(fmt.Sprintf("set -e; base64 -w0 %[1]s/%[2]s; echo; base64 -w0 %[1]s/%[3]s; echo", dir, BackupSealKeyFile, BackupMasterKeyFile))
+}
+
+// DumpCompleteMarker is the comment at the end of a complete pg_dumpall dump
+const DumpCompleteMarker = "PostgreSQL database cluster dump complete"
+
+// IsCompleteDump returns true if the pg_dumpall dump in the file ends with DumpCompleteMarker
+func IsCompleteDump(fileName string) (bool, error) {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return false, err
+	}
+	tail := make([]byte, 256)
+	offset := info.Size() - int64(len(tail))
+	if offset < 0 {
+		offset = 0
+	}
+	n, err := file.ReadAt(tail, offset)
+	if err != nil && err != io.EOF {
+		return false, err
+	}
+	return strings.Contains(string(tail[:n]), DumpCompleteMarker), nil
+}
+
+// GetBackupSecretName returns the name of the secret that holds the master key for the backup job of a Black Duck instance
+func GetBackupSecretName(name string) string {
+	return util.GetResourceName(name, util.BlackDuckName, "backup-job")
+}
+
+// NewBackupJob returns a job that dumps the database of a Black Duck instance with pg_dumpall. If claimName is set, the dump,
+// the seal key and the master key are stored in the backupDir directory of the PVC, with the master key read from the
+// GetBackupSecretName secret, otherwise the job waits for BackupDumpCommand to be run in its pod and exits with its exit code
+func NewBackupJob(namespace string, name string, postgres PostgresConnection, claimName string, backupDir string) *batchv1.Job {
+	command := execWaitCommand
+	container := corev1.Container{
+		Name:    "backup",
+		Image:   PostgresClientImage,
+		Command: []string{"/bin/bash"},
+		Env:     postgres.getEnvs(),
+	}
+	podSpec := corev1.PodSpec{RestartPolicy: corev1.RestartPolicyNever}
+
+	if len(claimName) > 0 {
+		dir := fmt.Sprintf("%s/%s", BackupMountPath, backupDir)
+		command = fmt.Sprintf(`set -e; mkdir -p %[1]s; pg_dumpall > %[1]s/%[2]s 2>/dev/termination-log; `+
+			`tail -c 256 %[1]s/%[2]s | grep -q "%[5]s" || { echo "the dump %[1]s/%[2]s is incomplete" >/dev/termination-log; exit 1; }; `+
+			`printf '%%s' "$SEAL_KEY" > %[1]s/%[3]s; printf '%%s' "$MASTER_KEY" > %[1]s/%[4]s`, dir, BackupDatabaseFile, BackupSealKeyFile, BackupMasterKeyFile, DumpCompleteMarker)
+		container.Env = append(container.Env,
+			getSecretEnv("SEAL_KEY", util.GetResourceName(name, util.BlackDuckName, "upload-cache"), "SEAL_KEY"),
+			getSecretEnv("MASTER_KEY", GetBackupSecretName(name), BackupMasterKeyKey),
+		)
+		volume, volumeMount := getBackupVolume(claimName, false)
+		container.VolumeMounts = []corev1.VolumeMount{volumeMount}
+		podSpec.Volumes = []corev1.Volume{volume}
+	}
+	container.Args = []string{"-c", command}
+	podSpec.Containers = []corev1.Container{container}
+
+	backoffLimit := int32(0)
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      util.GetResourceName(name, util.BlackDuckName, "backup-job"),
+			Namespace: namespace,
+			Labels:    map[string]string{"app": util.BlackDuckName, "name": name, "component": "backup-job"},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: corev1.PodTemplateSpec{
+				Spec: podSpec,
+			},
+		},
+	}
+}
+
+// getBackupVolume returns the volume and the volume mount of the backup PVC
+func getBackupVolume(claimName string, readOnly bool) (corev1.Volume, corev1.VolumeMount) {
+	volume := corev1.Volume{
+		Name: "backup",
+		VolumeSource: corev1.VolumeSource{
+			PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: claimName, ReadOnly: readOnly},
+		},
+	}
+	return volume, corev1.VolumeMount{Name: "backup", MountPath: BackupMountPath, ReadOnly: readOnly}
+}
+
+// NewBackupKeysJob returns a job that mounts the backup PVC and waits for BackupKeysCommand to be run in its pod
+func NewBackupKeysJob(namespace string, name string, claimName string) *batchv1.Job {
+	volume, volumeMount := getBackupVolume(claimName, true)
+	backoffLimit := int32(0)
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      util.GetResourceName(name, util.BlackDuckName, "backup-keys-job"),
+			Namespace: namespace,
+			Labels:    map[string]string{"app": util.BlackDuckName, "name": name, "component": "backup-keys-job"},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name:         "backup-keys",
+							Image:        PostgresClientImage,
+							Command:      []string{"/bin/bash"},
+							Args:         []string{"-c", execWaitCommand},
+							VolumeMounts: []corev1.VolumeMount{volumeMount},
+						},
+					},
+					Volumes:       []corev1.Volume{volume},
+					RestartPolicy: corev1.RestartPolicyNever,
+				},
+			},
+		},
+	}
+}
+
+// NewRestoreJob returns a job that restores a pg_dumpall dump into the database of a Black Duck instance and checks that the
+// Black Duck databases and tables exist afterwards. If claimName is set, the dump is read from the backupDir directory of the PVC,
+// otherwise it's read from stdin. The keys of a backup on a PVC are read with NewBackupKeysJob
+func NewRestoreJob(namespace string, name string, postgres PostgresConnection, claimName string, backupDir string) *batchv1.Job {
+	dumpFile := "-"
+	command := ""
+	container := corev1.Container{
+		Name:    "restore",
+		Image:   PostgresClientImage,
+		Command: []string{"/bin/bash"},
+		Env:     postgres.getEnvs(),
+	}
+	podSpec := corev1.PodSpec{RestartPolicy: corev1.RestartPolicyNever}
+
+	if len(claimName) > 0 {
+		dumpFile = fmt.Sprintf("%s/%s/%s", BackupMountPath, backupDir, BackupDatabaseFile)
+		command = fmt.Sprintf(`test -f %[1]s || { echo "%[1]s is missing" >/dev/termination-log; exit 1; }; `, dumpFile)
+		volume, volumeMount := getBackupVolume(claimName, true)
+		container.VolumeMounts = []corev1.VolumeMount{volumeMount}
+		podSpec.Volumes = []corev1.Volume{volume}
+	} else {
+		container.Stdin = true
+		container.StdinOnce = true
+	}
+
+	// a dump on the PVC is checked before anything is dropped, a local dump is checked before the job is created
+	if len(claimName) > 0 {
+		command += fmt.Sprintf(`tail -c 256 %[1]s | grep -q "%[2]s" || { echo "the dump %[1]s is incomplete" >/dev/termination-log; exit 1; }; `, dumpFile, DumpCompleteMarker)
+	}
+
+	// the databases are dropped so that the dump recreates them. The roles of the instance already exist, so creating them
+	// is skipped instead of failing, and psql stops at the first error, which goes to the termination log
+	command = fmt.Sprintf(`set -e -o pipefail; %sfor database in %s; do dropdb --if-exists "$database" 2>/dev/termination-log; done; `+
+		`sed -E 's/^CREATE ROLE ([^;]+);$/DO $$BEGIN CREATE ROLE \1; EXCEPTION WHEN duplicate_object THEN NULL; END$$;/' %s | `+
+		`psql -v ON_ERROR_STOP=1 -q -f - postgres >/dev/null 2>/dev/termination-log; `+
+		`for database in %[2]s; do psql -tAc "SELECT 1 FROM pg_database WHERE datname = '$database'" postgres | grep -q 1 || { echo "the $database database is missing after the restore" >/dev/termination-log; exit 1; }; done; `+
+		`[ "$(psql -tAc "SELECT count(*) FROM information_schema.tables WHERE table_schema = '%[4]s'" bds_hub)" -gt 0 ] || { echo "the %[4]s schema of the bds_hub database has no tables after the restore" >/dev/termination-log; exit 1; }`,
+		command, strings.Join(BlackDuckDatabases, " "), dumpFile, BlackDuckSchema)
+	container.Args = []string{"-c", command}
+	podSpec.Containers = []corev1.Container{container}
+
+	backoffLimit := int32(0)
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      util.GetResourceName(name, util.BlackDuckName, "restore-job"),
+			Namespace: namespace,
+			Labels:    map[string]string{"app": util.BlackDuckName, "name": name, "component": "restore-job"},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: corev1.PodTemplateSpec{
+				Spec: podSpec,
+			},
+		},
 	}
 }
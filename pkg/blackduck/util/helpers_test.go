@@ -0,0 +1,145 @@
+/*
+Copyright (C) 2020 Synopsys, Inc.
+
+Licensed to the Apache Software Foundation (ASF) under one
+or more contributor license agreements. See the NOTICE file
+distributed with this work for additional information
+regarding copyright ownership. The ASF licenses this file
+to you under the Apache License, Version 2.0 (the
+"License"); you may not use this file except in compliance
+with the License. You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing,
+software distributed under the License is distributed on an
+"AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+KIND, either express or implied. See the License for the
+specific language governing permissions and limitations
+under the License.
+*/
+
+package util
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+)
+
+// secretEnvs are the environment variables of the backup and restore jobs that hold secrets
+var secretEnvs = []string{"PGPASSWORD", "SEAL_KEY", "MASTER_KEY"}
+
+// assertNoInlineSecrets asserts that the containers of the job read their secrets from secrets instead of the job spec
+func assertNoInlineSecrets(t *testing.T, job *batchv1.Job, description string) {
+	for _, container := range job.Spec.Template.Spec.Containers {
+		for _, env := range container.Env {
+			for _, secretEnv := range secretEnvs {
+				if env.Name == secretEnv {
+					assert.Empty(t, env.Value, "%s: %s", description, env.Name)
+					if assert.NotNil(t, env.ValueFrom, "%s: %s", description, env.Name) {
+						assert.NotNil(t, env.ValueFrom.SecretKeyRef, "%s: %s", description, env.Name)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestIsCompleteDump(t *testing.T) {
+	dir, err := ioutil.TempDir("", "backup")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var tests = []struct {
+		description string
+		dump        string
+		complete    bool
+	}{
+		{description: "complete dump", dump: "CREATE DATABASE bds_hub;\n--\n-- " + DumpCompleteMarker + "\n--\n\n", complete: true},
+		{description: "truncated dump", dump: "CREATE DATABASE bds_hub;\nCOPY st.scan (id) FROM stdin;\n1\n", complete: false},
+		{description: "marker before the end of a long dump", dump: "-- " + DumpCompleteMarker + "\n" + string(make([]byte, 1024)), complete: false},
+		{description: "empty dump", dump: "", complete: false},
+	}
+
+	for _, test := range tests {
+		fileName := filepath.Join(dir, BackupDatabaseFile)
+		if err := ioutil.WriteFile(fileName, []byte(test.dump), 0600); err != nil {
+			t.Fatal(err)
+		}
+		complete, err := IsCompleteDump(fileName)
+		assert.NoError(t, err, test.description)
+		assert.Equal(t, test.complete, complete, test.description)
+	}
+
+	_, err = IsCompleteDump(filepath.Join(dir, "missing.sql"))
+	assert.Error(t, err)
+}
+
+func TestNewBackupJob(t *testing.T) {
+	postgres := PostgresConnection{Host: "bd1-blackduck-postgres.ns.svc.cluster.local", Port: "5432", User: "postgres", PasswordSecretName: "bd1-blackduck-db-creds"}
+
+	job := NewBackupJob("ns", "bd1", postgres, "backups", "ns-bd1-20200401200000")
+	assert.Equal(t, "bd1-blackduck-backup-job", job.Name)
+	assertNoInlineSecrets(t, job, "backup to a PVC")
+	container := job.Spec.Template.Spec.Containers[0]
+	assert.Contains(t, container.Args[1], BackupMountPath+"/ns-bd1-20200401200000/"+BackupMasterKeyFile)
+	assert.Equal(t, "backups", job.Spec.Template.Spec.Volumes[0].PersistentVolumeClaim.ClaimName)
+	for _, env := range container.Env {
+		switch env.Name {
+		case "PGPASSWORD":
+			assert.Equal(t, "bd1-blackduck-db-creds", env.ValueFrom.SecretKeyRef.Name)
+			assert.Equal(t, PostgresAdminPasswordKey, env.ValueFrom.SecretKeyRef.Key)
+		case "MASTER_KEY":
+			assert.Equal(t, GetBackupSecretName("bd1"), env.ValueFrom.SecretKeyRef.Name)
+			assert.Equal(t, BackupMasterKeyKey, env.ValueFrom.SecretKeyRef.Key)
+		}
+	}
+
+	// A local backup streams the dump over exec, so the job has no volume and no keys
+	job = NewBackupJob("ns", "bd1", postgres, "", "")
+	assertNoInlineSecrets(t, job, "local backup")
+	assert.Empty(t, job.Spec.Template.Spec.Volumes)
+	assert.Len(t, job.Spec.Template.Spec.Containers[0].Env, 4)
+}
+
+func TestNewRestoreJob(t *testing.T) {
+	postgres := PostgresConnection{Host: "bd1-blackduck-postgres.ns.svc.cluster.local", Port: "5432", User: "postgres", PasswordSecretName: "bd1-blackduck-db-creds"}
+
+	job := NewRestoreJob("ns", "bd1", postgres, "backups", "ns-bd1-20200401200000")
+	assert.Equal(t, "bd1-blackduck-restore-job", job.Name)
+	assertNoInlineSecrets(t, job, "restore from a PVC")
+	container := job.Spec.Template.Spec.Containers[0]
+	assert.Contains(t, container.Args[1], BackupMountPath+"/ns-bd1-20200401200000/"+BackupDatabaseFile)
+	// the keys are read by the backup keys job, so that they aren't written to the logs of the restore job
+	assert.NotContains(t, container.Args[1], BackupSealKeyFile)
+	assert.NotContains(t, container.Args[1], BackupMasterKeyFile)
+	assert.True(t, job.Spec.Template.Spec.Volumes[0].PersistentVolumeClaim.ReadOnly)
+	assert.False(t, container.Stdin)
+
+	// A local dump is copied to the stdin of the job
+	job = NewRestoreJob("ns", "bd1", postgres, "", "")
+	assertNoInlineSecrets(t, job, "local restore")
+	assert.True(t, job.Spec.Template.Spec.Containers[0].Stdin)
+	assert.Empty(t, job.Spec.Template.Spec.Volumes)
+}
+
+func TestNewBackupKeysJob(t *testing.T) {
+	job := NewBackupKeysJob("ns", "bd1", "backups")
+	assert.Equal(t, "bd1-blackduck-backup-keys-job", job.Name)
+	container := job.Spec.Template.Spec.Containers[0]
+	assert.Empty(t, container.Env)
+	assert.NotContains(t, container.Args[1], BackupSealKeyFile)
+	assert.True(t, container.VolumeMounts[0].ReadOnly)
+	assert.Equal(t, "backups", job.Spec.Template.Spec.Volumes[0].PersistentVolumeClaim.ClaimName)
+
+	command := BackupKeysCommand("ns-bd1-20200401200000")
+	assert.Contains(t, command[2], BackupMountPath+"/ns-bd1-20200401200000/"+BackupSealKeyFile)
+	assert.Contains(t, command[2], BackupMountPath+"/ns-bd1-20200401200000/"+BackupMasterKeyFile)
+}
@@ -112,7 +112,26 @@ var BDBAChartName = "bdba"
 var BDBAChartRepository = ""
 
 func init() {
-	IndexChartURLs, _ = util.GetChartURLs(BaseChartRepository, "")
+	util.SetChartRepositories([]*util.ChartRepository{{URL: BaseChartRepository}})
+}
+
+// LoadIndexChartURLs sets IndexChartURLs to the charts in the chart repositories in util.GetChartRepositories()
+// without changing the chart and version of the applications
+func LoadIndexChartURLs() error {
+	indexChartURLs, err := util.GetChartURLs("", "")
+	if err != nil {
+		return fmt.Errorf("unable to find the versions of the Synopsys applications: %s", err)
+	}
+	IndexChartURLs = indexChartURLs
+	return nil
+}
+
+// LoadChartVersions sets the latest chart and version of each application from the
+// chart repositories in util.GetChartRepositories()
+func LoadChartVersions() error {
+	if err := LoadIndexChartURLs(); err != nil {
+		return err
+	}
 
 	// Alert
 	AlertChartRepository, _ = util.GetLatestChartURLForApp(IndexChartURLs, AlertChartName)
@@ -135,11 +154,13 @@ func init() {
 	OpsSightVersion = OpsSightPackageNameSlice[1]
 
 	// Polaris
-	// TODO ...
+	if polarisChartURL, _ := util.GetLatestChartURLForAppVersion(IndexChartURLs, PolarisChartName, PolarisVersion); len(polarisChartURL) > 0 {
+		PolarisChartRepository = polarisChartURL
+	}
 
 	// Polaris Reporting
 	PolarisReportingChartRepository, _ = util.GetLatestChartURLForApp(IndexChartURLs, PolarisReportingChartName)
 	PolarisReportingPackageNameSlice := util.ParsePackageName(PolarisReportingChartRepository)
 	PolarisReportingVersion = PolarisReportingPackageNameSlice[1]
-
+	return nil
 }
@@ -0,0 +1,183 @@
+/*
+ * Copyright (C) 2019 Synopsys, Inc.
+ *
+ *  Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements. See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership. The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ *  with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations
+ *  under the License.
+ */
+
+package synopsysctl
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"time"
+
+	blackduckutil "github.com/blackducksoftware/synopsysctl/pkg/blackduck/util"
+	"github.com/blackducksoftware/synopsysctl/pkg/util"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"sigs.k8s.io/yaml"
+)
+
+// Checkpoint policies of an upgrade of Black Duck
+const (
+	checkpointBackup   = "backup"
+	checkpointSnapshot = "snapshot"
+	checkpointAll      = "all"
+)
+
+// checkpointPolicies are the valid values of --checkpoint
+var checkpointPolicies = []string{checkpointBackup, checkpointSnapshot, checkpointAll}
+
+// blackDuckCheckpoint is the state of a Black Duck instance that is saved before an upgrade
+type blackDuckCheckpoint struct {
+	name        string
+	namespace   string
+	revision    int    // revision of the release with the stopped instance
+	backup      string // location of the database backup
+	snapshotSet string // set of the volume snapshots
+}
+
+// restoreCommand returns the command that brings the instance back to the checkpoint: the rollback restores the
+// values of the stopped instance, and the volumes and the database are restored before the instance is started
+func (c *blackDuckCheckpoint) restoreCommand() string {
+	commands := []string{fmt.Sprintf("synopsysctl rollback blackduck %s -n %s --revision %d", c.name, c.namespace, c.revision)}
+	if len(c.snapshotSet) > 0 {
+		commands = append(commands, fmt.Sprintf("synopsysctl snapshot restore blackduck %s -n %s --set %s", c.name, c.namespace, c.snapshotSet))
+	}
+	if len(c.backup) > 0 {
+		commands = append(commands, fmt.Sprintf("synopsysctl restore blackduck %s -n %s --from %s", c.name, c.namespace, c.backup))
+	} else {
+		commands = append(commands, fmt.Sprintf("synopsysctl start blackduck %s -n %s", c.name, c.namespace))
+	}
+	return strings.Join(commands, " && ")
+}
+
+// getCheckpointOptions returns the checkpoint policy and the location of the checkpoint's backup from the flags, or from
+// the checkpoint and checkpointTo in the config file. The policy is empty if no checkpoint should be taken
+func getCheckpointOptions(flags *pflag.FlagSet) (string, string, error) {
+	policy := viper.GetString("checkpoint")
+	to := viper.GetString("checkpointTo")
+	if flags.Lookup("checkpoint").Changed {
+		policy = flags.Lookup("checkpoint").Value.String()
+	}
+	if flags.Lookup("checkpoint-to").Changed {
+		to = flags.Lookup("checkpoint-to").Value.String()
+	}
+	if noCheckpoint, _ := flags.GetBool("no-checkpoint"); noCheckpoint {
+		if flags.Lookup("checkpoint").Changed {
+			return "", "", fmt.Errorf("--checkpoint and --no-checkpoint can't be used together")
+		}
+		return "", "", nil
+	}
+	if len(policy) == 0 {
+		return "", "", nil
+	}
+	if !util.IsExistInStringSlice(checkpointPolicies, policy) {
+		return "", "", fmt.Errorf("--checkpoint must be one of %s", strings.Join(checkpointPolicies, ", "))
+	}
+	if policy != checkpointSnapshot {
+		if len(to) == 0 {
+			return "", "", fmt.Errorf("--checkpoint-to must be set to store the backup of the checkpoint")
+		}
+		if strings.HasPrefix(to, backupPVCPrefix) && len(strings.TrimPrefix(to, backupPVCPrefix)) == 0 {
+			return "", "", fmt.Errorf("--checkpoint-to must have the name of the PVC after '%s'", backupPVCPrefix)
+		}
+	}
+	return policy, to, nil
+}
+
+// checkpointBlackDuck stops a Black Duck instance and, depending on the policy, takes volume snapshots of its PVCs
+// and a backup of its database and keys to a local directory or a PVC. The instance stays stopped so that the upgrade
+// starts it with the new version
+func checkpointBlackDuck(namespace string, name string, policy string, to string, className string, timeout time.Duration) (*blackDuckCheckpoint, error) {
+	instance, err := util.GetWithHelm3(name, namespace, kubeConfigPath)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't find instance %s in namespace %s", name, namespace)
+	}
+	checkpoint := &blackDuckCheckpoint{name: name, namespace: namespace}
+	takeBackup := policy == checkpointBackup || policy == checkpointAll
+	takeSnapshot := policy == checkpointSnapshot || policy == checkpointAll
+
+	// the master key can only be read while the instance is running
+	var sealKey, masterKey string
+	if takeBackup {
+		if status, _ := util.GetValueFromRelease(instance, []string{"status"}).(string); strings.EqualFold(status, "Stopped") {
+			return nil, fmt.Errorf("the master key of Black Duck '%s' can't be backed up while it's stopped, start it, use --checkpoint %s, or skip the checkpoint with --no-checkpoint", name, checkpointSnapshot)
+		}
+		if sealKey, masterKey, err = getBlackDuckSealAndMasterKey(namespace, name); err != nil {
+			return nil, err
+		}
+	}
+
+	// the instance is stopped with the chart that it's deployed with, the new chart is only used by the upgrade
+	log.Infof("stopping Black Duck '%s' in namespace '%s' to take a checkpoint...", name, namespace)
+	values := map[string]interface{}{}
+	if err := util.DeepCopyHelmValuesMap(instance.Config, values); err != nil {
+		return nil, err
+	}
+	util.SetHelmValueInMap(values, []string{"status"}, "Stopped")
+	if err := util.UpdateValuesWithHelm3(name, namespace, values, kubeConfigPath); err != nil {
+		return nil, fmt.Errorf("failed to stop Black Duck due to %+v", err)
+	}
+	stoppedErr := func(err error) error {
+		return fmt.Errorf("%+v. Black Duck '%s' is stopped, run 'synopsysctl start blackduck %s -n %s' to start it again", err, name, name, namespace)
+	}
+	stoppedInstance, err := util.GetWithHelm3(name, namespace, kubeConfigPath)
+	if err != nil {
+		return nil, stoppedErr(fmt.Errorf("couldn't find instance %s in namespace %s", name, namespace))
+	}
+	checkpoint.revision = stoppedInstance.Version
+	if err := util.WaitForPodsStopped(kubeClient, namespace, getInstanceLabelSelector(util.BlackDuckName, name), timeout); err != nil {
+		return nil, stoppedErr(fmt.Errorf("Black Duck '%s' didn't stop: %s", name, err))
+	}
+
+	// the volumes are snapshotted before the database is started for the backup
+	if takeSnapshot {
+		if checkpoint.snapshotSet, err = takeInstanceSnapshots(util.BlackDuckName, name, className); err != nil {
+			return nil, stoppedErr(err)
+		}
+	}
+
+	if takeBackup {
+		if !isBlackDuckPostgresExternal(instance) {
+			if err := startBlackDuckPostgres(namespace, name, timeout); err != nil {
+				return nil, stoppedErr(err)
+			}
+		}
+		if checkpoint.backup, err = dumpBlackDuck(instance, namespace, name, to, sealKey, masterKey, timeout); err != nil {
+			return nil, stoppedErr(err)
+		}
+
+		// the values are also kept in the revision of the release, a local backup gets a copy of them
+		if !strings.HasPrefix(checkpoint.backup, backupPVCPrefix) {
+			valuesYAML, err := yaml.Marshal(instance.Config)
+			if err != nil {
+				return nil, stoppedErr(fmt.Errorf("unable to convert the values of Black Duck '%s' to YAML due to %+v", name, err))
+			}
+			valuesFileName := filepath.Join(checkpoint.backup, blackduckutil.BackupValuesFile)
+			if err := ioutil.WriteFile(valuesFileName, valuesYAML, 0600); err != nil {
+				return nil, stoppedErr(fmt.Errorf("error writing to file '%s' due to %+v", valuesFileName, err))
+			}
+		}
+	}
+	log.Infof("took a checkpoint of Black Duck '%s' in namespace '%s' at revision %d", name, namespace, checkpoint.revision)
+	return checkpoint, nil
+}
@@ -0,0 +1,252 @@
+/*
+Copyright (C) 2019 Synopsys, Inc.
+
+Licensed to the Apache Software Foundation (ASF) under one
+or more contributor license agreements. See the NOTICE file
+distributed with this work for additional information
+regarding copyright ownership. The ASF licenses this file
+to you under the Apache License, Version 2.0 (the
+"License"); you may not use this file except in compliance
+with the License. You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing,
+software distributed under the License is distributed on an
+"AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+KIND, either express or implied. See the License for the
+specific language governing permissions and limitations
+under the License.
+*/
+
+package synopsysctl
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	blackduckutil "github.com/blackducksoftware/synopsysctl/pkg/blackduck/util"
+	"github.com/blackducksoftware/synopsysctl/pkg/util"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"helm.sh/helm/v3/pkg/release"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Backup Command Options and Defaults
+var backupTo = ""
+var backupTimeout = 30 * time.Minute
+
+// backupPVCPrefix is the prefix of a backup location on a PVC, e.g. pvc:<claim name>
+const backupPVCPrefix = "pvc:"
+
+// isBlackDuckPostgresExternal returns true if a Black Duck instance uses an external database
+func isBlackDuckPostgresExternal(helmRelease *release.Release) bool {
+	isExternal, _ := util.GetValueFromRelease(helmRelease, []string{"postgres", "isExternal"}).(bool)
+	return isExternal
+}
+
+// getBlackDuckPostgresConnection returns the connection to the internal or external database of a Black Duck instance
+func getBlackDuckPostgresConnection(helmRelease *release.Release, namespace string, name string) (blackduckutil.PostgresConnection, error) {
+	// the jobs read the password from the secret, it's only checked here
+	if _, _, err := blackduckutil.GetHubDBPassword(kubeClient, namespace, name); err != nil {
+		return blackduckutil.PostgresConnection{}, fmt.Errorf("unable to get the database credentials of Black Duck '%s' in namespace '%s' due to %+v", name, namespace, err)
+	}
+	postgres := blackduckutil.PostgresConnection{
+		Host:               fmt.Sprintf("%s.%s.svc.cluster.local", util.GetResourceName(name, util.BlackDuckName, "postgres"), namespace),
+		Port:               "5432",
+		User:               "postgres",
+		PasswordSecretName: util.GetResourceName(name, util.BlackDuckName, "db-creds"),
+	}
+	if isBlackDuckPostgresExternal(helmRelease) {
+		postgres.Host = fmt.Sprintf("%v", util.GetValueFromRelease(helmRelease, []string{"postgres", "host"}))
+		if port := util.GetValueFromRelease(helmRelease, []string{"postgres", "port"}); port != nil {
+			postgres.Port = fmt.Sprintf("%v", port)
+		}
+		if user, ok := util.GetValueFromRelease(helmRelease, []string{"postgres", "adminUserName"}).(string); ok && len(user) > 0 {
+			postgres.User = user
+		}
+		postgres.SSL, _ = util.GetValueFromRelease(helmRelease, []string{"postgres", "ssl"}).(bool)
+	}
+	return postgres, nil
+}
+
+// runJob creates the job and deletes it once it's done. If stdin is set, it's copied to the job
+func runJob(job *batchv1.Job, stdin io.Reader, timeout time.Duration) error {
+	if _, err := kubeClient.BatchV1().Jobs(job.Namespace).Create(job); err != nil {
+		return fmt.Errorf("unable to create job '%s' in namespace '%s' due to %+v", job.Name, job.Namespace, err)
+	}
+	defer func() {
+		propagationPolicy := metav1.DeletePropagationBackground
+		if err := kubeClient.BatchV1().Jobs(job.Namespace).Delete(job.Name, &metav1.DeleteOptions{PropagationPolicy: &propagationPolicy}); err != nil {
+			log.Warnf("unable to delete job '%s' in namespace '%s' due to %+v", job.Name, job.Namespace, err)
+		}
+	}()
+
+	if stdin != nil {
+		pod, err := util.WaitForJobPod(kubeClient, job.Namespace, job.Name, timeout)
+		if err != nil {
+			return err
+		}
+		if err := util.AttachContainer(restconfig, util.CreateAttachContainerRequest(kubeClient, pod), stdin); err != nil {
+			return fmt.Errorf("unable to copy the input to pod '%s' in namespace '%s' due to %+v", pod.Name, job.Namespace, err)
+		}
+	}
+	return util.WaitForJob(kubeClient, job.Namespace, job.Name, timeout)
+}
+
+// runJobCommand creates the job, runs the command in its pod and copies the output of the command to stdout, and
+// deletes the job once it's done
+func runJobCommand(job *batchv1.Job, command []string, stdout io.Writer, timeout time.Duration) error {
+	if _, err := kubeClient.BatchV1().Jobs(job.Namespace).Create(job); err != nil {
+		return fmt.Errorf("unable to create job '%s' in namespace '%s' due to %+v", job.Name, job.Namespace, err)
+	}
+	defer func() {
+		propagationPolicy := metav1.DeletePropagationBackground
+		if err := kubeClient.BatchV1().Jobs(job.Namespace).Delete(job.Name, &metav1.DeleteOptions{PropagationPolicy: &propagationPolicy}); err != nil {
+			log.Warnf("unable to delete job '%s' in namespace '%s' due to %+v", job.Name, job.Namespace, err)
+		}
+	}()
+
+	pod, err := util.WaitForJobPod(kubeClient, job.Namespace, job.Name, timeout)
+	if err != nil {
+		return err
+	}
+	var stderr bytes.Buffer
+	if err := util.StreamContainer(restconfig, util.CreateExecCommandRequest(kubeClient, pod, command), stdout, &stderr); err != nil {
+		return fmt.Errorf("unable to run the command in pod '%s' in namespace '%s' due to %+v: %s", pod.Name, job.Namespace, err, strings.TrimSpace(stderr.String()))
+	}
+	return util.WaitForJob(kubeClient, job.Namespace, job.Name, timeout)
+}
+
+// backupBlackDuck dumps the database of a Black Duck instance and captures its seal key and master key in a new
+// directory of the location, which is a local directory or a PVC. It returns where the backup is stored
+func backupBlackDuck(namespace string, name string, to string, timeout time.Duration) (string, error) {
+	helmRelease, err := util.GetWithHelm3(name, namespace, kubeConfigPath)
+	if err != nil {
+		return "", fmt.Errorf("couldn't find instance %s in namespace %s", name, namespace)
+	}
+	sealKey, masterKey, err := getBlackDuckSealAndMasterKey(namespace, name)
+	if err != nil {
+		return "", err
+	}
+	return dumpBlackDuck(helmRelease, namespace, name, to, sealKey, masterKey, timeout)
+}
+
+// dumpBlackDuck dumps the database of a Black Duck instance and stores it with the seal key and the master key in a new
+// directory of the location. The keys are passed in because the master key can't be read while the instance is stopped
+func dumpBlackDuck(helmRelease *release.Release, namespace string, name string, to string, sealKey string, masterKey string, timeout time.Duration) (string, error) {
+	postgres, err := getBlackDuckPostgresConnection(helmRelease, namespace, name)
+	if err != nil {
+		return "", err
+	}
+	backupDir := fmt.Sprintf("%s-%s-%s", namespace, name, time.Now().UTC().Format("20060102150405"))
+
+	// the dump and the keys are written to the PVC by the job
+	if strings.HasPrefix(to, backupPVCPrefix) {
+		claimName := strings.TrimPrefix(to, backupPVCPrefix)
+		if _, err := util.GetPVC(kubeClient, namespace, claimName); err != nil {
+			return "", fmt.Errorf("unable to find PVC '%s' in namespace '%s' due to %+v", claimName, namespace, err)
+		}
+		// the master key is passed to the job in a secret that only exists while the job runs
+		secretName := blackduckutil.GetBackupSecretName(name)
+		if _, err := util.CreateSecret(kubeClient, namespace, secretName, map[string]string{blackduckutil.BackupMasterKeyKey: masterKey}); err != nil {
+			return "", fmt.Errorf("unable to create secret '%s' in namespace '%s' due to %+v", secretName, namespace, err)
+		}
+		defer func() {
+			if err := util.DeleteSecret(kubeClient, namespace, secretName); err != nil {
+				log.Warnf("unable to delete secret '%s' in namespace '%s' due to %+v", secretName, namespace, err)
+			}
+		}()
+		log.Infof("backing up Black Duck '%s' in namespace '%s' to PVC '%s'...", name, namespace, claimName)
+		if err := runJob(blackduckutil.NewBackupJob(namespace, name, postgres, claimName, backupDir), nil, timeout); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s%s/%s", backupPVCPrefix, claimName, backupDir), nil
+	}
+
+	// the dump is streamed from the job to a local file, next to the keys
+	dir := filepath.Join(to, backupDir)
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return "", fmt.Errorf("unable to create directory '%s' due to %+v", dir, err)
+	}
+	keys := map[string]string{blackduckutil.BackupSealKeyFile: sealKey, blackduckutil.BackupMasterKeyFile: masterKey}
+	for fileName, key := range keys {
+		if err := ioutil.WriteFile(filepath.Join(dir, fileName), []byte(key), 0600); err != nil {
+			os.RemoveAll(dir)
+			return "", fmt.Errorf("error writing to file '%s' due to %+v", filepath.Join(dir, fileName), err)
+		}
+	}
+	dumpFileName := filepath.Join(dir, blackduckutil.BackupDatabaseFile)
+	dumpFile, err := os.OpenFile(dumpFileName, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		os.RemoveAll(dir)
+		return "", fmt.Errorf("unable to create file '%s' due to %+v", dumpFileName, err)
+	}
+	defer dumpFile.Close()
+	log.Infof("backing up Black Duck '%s' in namespace '%s' to '%s'...", name, namespace, dir)
+	if err := runJobCommand(blackduckutil.NewBackupJob(namespace, name, postgres, "", ""), blackduckutil.BackupDumpCommand, dumpFile, timeout); err != nil {
+		os.RemoveAll(dir)
+		return "", err
+	}
+	if complete, err := blackduckutil.IsCompleteDump(dumpFileName); err != nil || !complete {
+		os.RemoveAll(dir)
+		return "", fmt.Errorf("the dump of the database is incomplete, it doesn't end with '%s'", blackduckutil.DumpCompleteMarker)
+	}
+	return dir, nil
+}
+
+// backupCmd backs up a Synopsys resource
+var backupCmd = &cobra.Command{
+	Use:   "backup",
+	Short: "Back up a Synopsys resource",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return fmt.Errorf("must specify a sub-command")
+	},
+}
+
+// backupBlackDuckCmd backs up the database, the seal key and the master key of a Black Duck instance
+var backupBlackDuckCmd = &cobra.Command{
+	Use:           "blackduck NAME -n NAMESPACE --to DIRECTORY|pvc:CLAIM_NAME",
+	Example:       "synopsysctl backup blackduck <name> -n <namespace> --to <directory>\nsynopsysctl backup blackduck <name> -n <namespace> --to pvc:<claim name>",
+	Short:         "Back up the database, the seal key and the master key of a Black Duck instance",
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			cmd.Help()
+			return fmt.Errorf("this command takes 1 argument, but got %+v", args)
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if strings.HasPrefix(backupTo, backupPVCPrefix) && len(strings.TrimPrefix(backupTo, backupPVCPrefix)) == 0 {
+			return fmt.Errorf("--to must have the name of the PVC after '%s'", backupPVCPrefix)
+		}
+		start := time.Now()
+		location, err := backupBlackDuck(namespace, args[0], backupTo, backupTimeout)
+		if err != nil {
+			return fmt.Errorf("failed to back up Black Duck '%s' in namespace '%s' due to %+v", args[0], namespace, err)
+		}
+		log.Infof("successfully backed up Black Duck '%s' in namespace '%s' to '%s' in %s", args[0], namespace, location, time.Since(start).Round(time.Second))
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(backupCmd)
+
+	backupBlackDuckCmd.Flags().StringVarP(&namespace, "namespace", "n", namespace, "Namespace of the instance")
+	cobra.MarkFlagRequired(backupBlackDuckCmd.Flags(), "namespace")
+	backupBlackDuckCmd.Flags().StringVar(&backupTo, "to", backupTo, "Local directory or PVC in the instance's namespace (pvc:<claim name>) to store the backup in, a new directory is created in it for each backup")
+	cobra.MarkFlagRequired(backupBlackDuckCmd.Flags(), "to")
+	backupBlackDuckCmd.Flags().DurationVar(&backupTimeout, "timeout", backupTimeout, "How long to wait for the database dump to complete")
+	backupCmd.AddCommand(backupBlackDuckCmd)
+}
@@ -0,0 +1,89 @@
+/*
+Copyright (C) 2020 Synopsys, Inc.
+
+Licensed to the Apache Software Foundation (ASF) under one
+or more contributor license agreements. See the NOTICE file
+distributed with this work for additional information
+regarding copyright ownership. The ASF licenses this file
+to you under the Apache License, Version 2.0 (the
+"License"); you may not use this file except in compliance
+with the License. You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing,
+software distributed under the License is distributed on an
+"AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+KIND, either express or implied. See the License for the
+specific language governing permissions and limitations
+under the License.
+*/
+
+package synopsysctl
+
+import (
+	"fmt"
+
+	"github.com/blackducksoftware/synopsysctl/pkg/util"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// Bundle Command Options and Defaults
+var bundleVersion = ""
+var bundlePath = ""
+
+// bundleCmd manages installation bundles for air-gapped clusters
+var bundleCmd = &cobra.Command{
+	Use:   "bundle",
+	Short: "Manage installation bundles for clusters without access to the chart repository",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return fmt.Errorf("must specify a sub-command")
+	},
+}
+
+// bundleCreateCmd packages everything needed to install a product into a single archive
+var bundleCreateCmd = &cobra.Command{
+	Use:           "create PRODUCT",
+	Example:       "synopsysctl bundle create blackduck --version 2020.4.0\nsynopsysctl bundle create alert --version 5.3.0 --path alert-bundle.tgz",
+	Short:         "Create an installation bundle for a product",
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			cmd.Help()
+			return fmt.Errorf("this command takes 1 argument, but got %+v", args)
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		chartURL, err := getChartURL(args[0], bundleVersion)
+		if err != nil {
+			return err
+		}
+
+		path := bundlePath
+		if len(path) == 0 {
+			path = fmt.Sprintf("%s-%s-bundle.tgz", args[0], util.ParsePackageName(chartURL)[1])
+		}
+		bundleManifest, err := util.CreateBundle(path, args[0], chartURL)
+		if err != nil {
+			return fmt.Errorf("failed to create the bundle for '%s': %+v", args[0], err)
+		}
+
+		log.Infof("bundle for %s version '%s' has been successfully created at '%s'", args[0], bundleManifest.AppVersion, path)
+		log.Infof("the bundle uses %d images, push them to a registry the cluster can access:", len(bundleManifest.Images))
+		for _, image := range bundleManifest.Images {
+			fmt.Printf("%s\n", image)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(bundleCmd)
+
+	bundleCreateCmd.Flags().StringVar(&bundleVersion, "version", bundleVersion, "Version of the application to bundle (defaults to the latest)")
+	bundleCreateCmd.Flags().StringVar(&bundlePath, "path", bundlePath, "Path to write the bundle to (defaults to <product>-<version>-bundle.tgz)")
+	bundleCmd.AddCommand(bundleCreateCmd)
+}
@@ -0,0 +1,202 @@
+/*
+Copyright (C) 2020 Synopsys, Inc.
+
+Licensed to the Apache Software Foundation (ASF) under one
+or more contributor license agreements. See the NOTICE file
+distributed with this work for additional information
+regarding copyright ownership. The ASF licenses this file
+to you under the Apache License, Version 2.0 (the
+"License"); you may not use this file except in compliance
+with the License. You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing,
+software distributed under the License is distributed on an
+"AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+KIND, either express or implied. See the License for the
+specific language governing permissions and limitations
+under the License.
+*/
+
+package synopsysctl
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/blackducksoftware/synopsysctl/pkg/globals"
+	"github.com/blackducksoftware/synopsysctl/pkg/util"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// Chart Command Options and Defaults
+var chartPullVersion = ""
+var chartPruneKeep = 1
+
+// chartNames maps the product names used by synopsysctl commands to their chart names
+var chartNames = map[string]string{
+	util.AlertName:               globals.AlertChartName,
+	util.BlackDuckName:           globals.BlackDuckChartName,
+	util.OpsSightName:            globals.OpsSightChartName,
+	globals.BDBAName:             globals.BDBAChartName,
+	globals.PolarisName:          globals.PolarisChartName,
+	globals.PolarisReportingName: globals.PolarisReportingChartName,
+}
+
+// getChartName returns the chart name of a product
+func getChartName(product string) (string, error) {
+	chartName, ok := chartNames[product]
+	if !ok {
+		products := []string{}
+		for name := range chartNames {
+			products = append(products, name)
+		}
+		return "", fmt.Errorf("'%s' is not a valid product [%s]", product, strings.Join(products, "|"))
+	}
+	return chartName, nil
+}
+
+// getChartURL returns the location of the chart for a version of a product, using the
+// latest version if version is empty
+func getChartURL(product, version string) (string, error) {
+	chartName, err := getChartName(product)
+	if err != nil {
+		return "", err
+	}
+	var chartURL string
+	if len(version) > 0 {
+		chartURL, err = util.GetLatestChartURLForAppVersion(globals.IndexChartURLs, chartName, version)
+	} else {
+		chartURL, err = util.GetLatestChartURLForApp(globals.IndexChartURLs, chartName)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to find the chart for '%s': %+v", product, err)
+	}
+	if len(chartURL) == 0 {
+		return "", fmt.Errorf("unable to find a chart for '%s' in the chart repository or the chart cache", product)
+	}
+	return chartURL, nil
+}
+
+// chartCmd manages the local chart cache
+var chartCmd = &cobra.Command{
+	Use:   "chart",
+	Short: "Manage the local cache of Synopsys charts",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return fmt.Errorf("must specify a sub-command")
+	},
+}
+
+// chartPullCmd downloads a chart into the local chart cache
+var chartPullCmd = &cobra.Command{
+	Use:           "pull PRODUCT",
+	Example:       "synopsysctl chart pull blackduck\nsynopsysctl chart pull alert --version 5.3.0",
+	Short:         "Download a chart into the local chart cache",
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			cmd.Help()
+			return fmt.Errorf("this command takes 1 argument, but got %+v", args)
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		chartURL, err := getChartURL(args[0], chartPullVersion)
+		if err != nil {
+			return err
+		}
+
+		chartPath, err := util.GetChartCache().Pull(chartURL)
+		if err != nil {
+			return fmt.Errorf("failed to pull the chart for '%s': %+v", args[0], err)
+		}
+		log.Infof("chart '%s' has been successfully pulled to '%s'", filepath.Base(chartPath), filepath.Dir(chartPath))
+		return nil
+	},
+}
+
+// chartListCmd lists the charts in the local chart cache
+var chartListCmd = &cobra.Command{
+	Use:           "list",
+	Example:       "synopsysctl chart list",
+	Short:         "List the charts in the local chart cache",
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 0 {
+			cmd.Help()
+			return fmt.Errorf("this command takes 0 arguments, but got %+v", args)
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		chartCache := util.GetChartCache()
+		if _, err := os.Stat(chartCache.Dir); os.IsNotExist(err) {
+			log.Infof("the chart cache '%s' is empty", chartCache.Dir)
+			return nil
+		}
+		chartVersions, err := chartCache.List()
+		if err != nil {
+			return fmt.Errorf("failed to list the chart cache: %+v", err)
+		}
+
+		table := util.Table{Columns: []util.TableColumn{{Header: "NAME"}, {Header: "VERSION"}, {Header: "APP VERSION"}, {Header: "FILE"}, {Header: "DIGEST", Wide: true}}}
+		for _, chartVersion := range chartVersions {
+			table.Rows = append(table.Rows, []string{chartVersion.Name, chartVersion.Version, chartVersion.AppVersion, strings.Join(chartVersion.URLs, ","), chartVersion.Digest})
+		}
+		return printOutput(cmd, chartVersions, table)
+	},
+}
+
+// chartPruneCmd removes old charts from the local chart cache
+var chartPruneCmd = &cobra.Command{
+	Use:           "prune",
+	Example:       "synopsysctl chart prune\nsynopsysctl chart prune --keep 3",
+	Short:         "Remove old charts from the local chart cache",
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 0 {
+			cmd.Help()
+			return fmt.Errorf("this command takes 0 arguments, but got %+v", args)
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if chartPruneKeep < 0 {
+			return fmt.Errorf("--keep must be 0 or greater, but got %d", chartPruneKeep)
+		}
+		chartCache := util.GetChartCache()
+		if _, err := os.Stat(chartCache.Dir); os.IsNotExist(err) {
+			log.Infof("the chart cache '%s' is empty", chartCache.Dir)
+			return nil
+		}
+		removed, err := chartCache.Prune(chartPruneKeep)
+		for _, chartPath := range removed {
+			log.Infof("removed '%s'", filepath.Base(chartPath))
+		}
+		if err != nil {
+			return fmt.Errorf("failed to prune the chart cache: %+v", err)
+		}
+		log.Infof("removed %d chart(s) from the chart cache", len(removed))
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(chartCmd)
+
+	chartPullCmd.Flags().StringVar(&chartPullVersion, "version", chartPullVersion, "Version of the application to pull the chart for (defaults to the latest)")
+	chartCmd.AddCommand(chartPullCmd)
+
+	addOutputFlag(chartListCmd, util.OutputTable)
+	chartCmd.AddCommand(chartListCmd)
+
+	chartPruneCmd.Flags().IntVar(&chartPruneKeep, "keep", chartPruneKeep, "Number of versions of each chart to keep")
+	chartCmd.AddCommand(chartPruneCmd)
+}
@@ -0,0 +1,233 @@
+/*
+Copyright (C) 2019 Synopsys, Inc.
+
+Licensed to the Apache Software Foundation (ASF) under one
+or more contributor license agreements. See the NOTICE file
+distributed with this work for additional information
+regarding copyright ownership. The ASF licenses this file
+to you under the Apache License, Version 2.0 (the
+"License"); you may not use this file except in compliance
+with the License. You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing,
+software distributed under the License is distributed on an
+"AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+KIND, either express or implied. See the License for the
+specific language governing permissions and limitations
+under the License.
+*/
+
+package synopsysctl
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/blackducksoftware/synopsysctl/pkg/blackduck"
+	blackduckutil "github.com/blackducksoftware/synopsysctl/pkg/blackduck/util"
+	"github.com/blackducksoftware/synopsysctl/pkg/globals"
+	"github.com/blackducksoftware/synopsysctl/pkg/util"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Clone Command Options and Defaults
+var cloneToName = ""
+var cloneToNamespace = ""
+
+// blackDuckCertificateSecrets are the values of the certificate secrets of a Black Duck instance and the suffixes of their names
+var blackDuckCertificateSecrets = map[string]string{
+	"tlsCertSecretName":        "webserver-certificate",
+	"proxyCertSecretName":      "proxy-certificate",
+	"certAuthCACertSecretName": "auth-custom-ca",
+}
+
+// cloneBlackDuckSecrets copies the certificate secrets of the source Black Duck instance to the namespace of the target
+// instance, and sets the values of the target instance to the names of the copies
+func cloneBlackDuckSecrets(values map[string]interface{}, fromNamespace string, toNamespace string, to string) error {
+	for key, suffix := range blackDuckCertificateSecrets {
+		secretName, ok := values[key].(string)
+		if !ok || len(secretName) == 0 {
+			continue
+		}
+		secret, err := util.GetSecret(kubeClient, fromNamespace, secretName)
+		if err != nil {
+			return fmt.Errorf("unable to find secret '%s' in namespace '%s' due to %+v", secretName, fromNamespace, err)
+		}
+		newSecret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      util.GetResourceName(to, util.BlackDuckName, suffix),
+				Namespace: toNamespace,
+			},
+			Type: secret.Type,
+			Data: secret.Data,
+		}
+		if _, err := kubeClient.CoreV1().Secrets(toNamespace).Create(newSecret); err != nil && !k8serrors.IsAlreadyExists(err) {
+			return fmt.Errorf("failed to create certificate secret '%s' in namespace '%s' due to %+v", newSecret.Name, toNamespace, err)
+		}
+		values[key] = newSecret.Name
+	}
+	return nil
+}
+
+// cloneBlackDuck creates a Black Duck instance with the values, the certificates, the seal key and a copy of the database
+// of another instance. The target instance is created stopped, and started once the database has been copied
+func cloneBlackDuck(cmd *cobra.Command, namespace string, name string, toNamespace string, to string) error {
+	instance, err := util.GetWithHelm3(name, namespace, kubeConfigPath)
+	if err != nil {
+		return fmt.Errorf("couldn't find instance %s in namespace %s", name, namespace)
+	}
+	if isBlackDuckPostgresExternal(instance) {
+		return fmt.Errorf("Black Duck '%s' uses an external database, only instances with an internal database can be cloned", name)
+	}
+	if status, _ := util.GetValueFromRelease(instance, []string{"status"}).(string); strings.EqualFold(status, "Stopped") {
+		return fmt.Errorf("Black Duck '%s' is stopped, its database has to be running to be cloned", name)
+	}
+	_, adminPassword, err := blackduckutil.GetHubDBPassword(kubeClient, namespace, name)
+	if err != nil {
+		return fmt.Errorf("unable to get the database credentials of Black Duck '%s' in namespace '%s' due to %+v", name, namespace, err)
+	}
+
+	// the target instance gets the values of the source instance, and its current seal key
+	helmValuesMap := map[string]interface{}{}
+	if err := util.DeepCopyHelmValuesMap(instance.Config, helmValuesMap); err != nil {
+		return fmt.Errorf("failed to copy the values of Black Duck '%s' due to %+v", name, err)
+	}
+	secret, err := util.GetSecret(kubeClient, namespace, util.GetResourceName(name, util.BlackDuckName, "upload-cache"))
+	if err != nil {
+		return fmt.Errorf("unable to find Seal key secret (%s-blackduck-upload-cache) in namespace '%s' due to %+v", name, namespace, err)
+	}
+	if sealKey := string(secret.Data["SEAL_KEY"]); len(sealKey) > 0 {
+		util.SetHelmValueInMap(helmValuesMap, []string{"sealKey"}, sealKey)
+	}
+	util.SetHelmValueInMap(helmValuesMap, []string{"status"}, "Stopped")
+
+	// Update the Helm Chart Location
+	blackDuckVersionFromRelease := util.GetValueFromRelease(instance, []string{"imageTag"}).(string)
+	err = SetHelmChartLocation(cmd.Flags(), globals.BlackDuckChartName, blackDuckVersionFromRelease, &globals.BlackDuckChartRepository)
+	if err != nil {
+		return fmt.Errorf("failed to set the app resources location due to %+v", err)
+	}
+
+	if _, err := util.GetNamespace(kubeClient, toNamespace); err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return fmt.Errorf("unable to get namespace '%s' due to %+v", toNamespace, err)
+		}
+		if _, err := util.CreateNamespace(kubeClient, toNamespace); err != nil {
+			return fmt.Errorf("unable to create namespace '%s' due to %+v", toNamespace, err)
+		}
+	}
+	if err := cloneBlackDuckSecrets(helmValuesMap, namespace, toNamespace, to); err != nil {
+		return err
+	}
+
+	var extraFiles []string
+	size, found := helmValuesMap["size"]
+	if found {
+		extraFiles = append(extraFiles, fmt.Sprintf("%s.yaml", strings.ToLower(size.(string))))
+	}
+
+	// Check Dry Run before deploying any resources
+	err = util.CreateWithHelm3(to, toNamespace, globals.BlackDuckChartRepository, helmValuesMap, kubeConfigPath, true, extraFiles...)
+	if err != nil {
+		return fmt.Errorf("failed to create Blackduck resources: %+v", err)
+	}
+
+	log.Infof("creating Black Duck '%s' in namespace '%s' with the values of Black Duck '%s'...", to, toNamespace, name)
+	err = util.CreateWithHelm3(to, toNamespace, globals.BlackDuckChartRepository, helmValuesMap, kubeConfigPath, false, extraFiles...)
+	if err != nil {
+		return fmt.Errorf("failed to create Blackduck resources: %+v", err)
+	}
+	createdErr := func(err error) error {
+		return fmt.Errorf("%+v. Black Duck '%s' has been created in namespace '%s', run 'synopsysctl delete blackduck %s -n %s' to remove it", err, to, toNamespace, to, toNamespace)
+	}
+
+	// only the database of the target instance is started until the database has been copied
+	if err := startBlackDuckPostgres(toNamespace, to, util.DefaultWaitTimeout); err != nil {
+		return createdErr(err)
+	}
+
+	log.Infof("copying the database of Black Duck '%s' in namespace '%s' to Black Duck '%s' in namespace '%s'...", name, namespace, to, toNamespace)
+	err = blackduckutil.CloneJob(kubeClient, namespace, name, toNamespace, to, adminPassword)
+	propagationPolicy := metav1.DeletePropagationBackground
+	if err := kubeClient.BatchV1().Jobs(toNamespace).Delete(util.GetResourceName(to, util.BlackDuckName, "clone-job"), &metav1.DeleteOptions{PropagationPolicy: &propagationPolicy}); err != nil && !k8serrors.IsNotFound(err) {
+		log.Warnf("unable to delete the clone job in namespace '%s' due to %+v", toNamespace, err)
+	}
+	if err != nil {
+		return createdErr(fmt.Errorf("unable to copy the database due to %+v", err))
+	}
+
+	log.Infof("starting Black Duck '%s' in namespace '%s'...", to, toNamespace)
+	newInstance, err := util.GetWithHelm3(to, toNamespace, kubeConfigPath)
+	if err != nil {
+		return createdErr(fmt.Errorf("couldn't find instance %s in namespace %s", to, toNamespace))
+	}
+	if err := setBlackDuckStatus(cmd.Flags(), newInstance, "Running"); err != nil {
+		return createdErr(err)
+	}
+
+	return blackduck.CRUDServiceOrRoute(restconfig, kubeClient, toNamespace, to, util.GetValueFromRelease(instance, []string{"exposeui"}), util.GetValueFromRelease(instance, []string{"exposedServiceType"}), false)
+}
+
+// cloneCmd clones a Synopsys resource
+var cloneCmd = &cobra.Command{
+	Use:   "clone",
+	Short: "Clone a Synopsys resource",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return fmt.Errorf("must specify a sub-command")
+	},
+}
+
+// cloneBlackDuckCmd clones a Black Duck instance with its database into another namespace
+var cloneBlackDuckCmd = &cobra.Command{
+	Use:           "blackduck NAME -n NAMESPACE --to NEW_NAME [--to-namespace NEW_NAMESPACE]",
+	Example:       "synopsysctl clone blackduck <name> -n <namespace> --to <new name> --to-namespace <new namespace>",
+	Short:         "Create a copy of a Black Duck instance and its database",
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			cmd.Help()
+			return fmt.Errorf("this command takes 1 argument, but got %+v", args)
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		toNamespace := cloneToNamespace
+		if len(toNamespace) == 0 {
+			toNamespace = namespace
+		}
+		if toNamespace == namespace && cloneToName == args[0] {
+			return fmt.Errorf("the clone must have a different name or namespace than Black Duck '%s'", args[0])
+		}
+		if err := cloneBlackDuck(cmd, namespace, args[0], toNamespace, cloneToName); err != nil {
+			return fmt.Errorf("failed to clone Black Duck '%s' in namespace '%s' due to %+v", args[0], namespace, err)
+		}
+		log.Infof("successfully cloned Black Duck '%s' in namespace '%s' to Black Duck '%s' in namespace '%s'", args[0], namespace, cloneToName, toNamespace)
+
+		// the new instance is in the target namespace
+		namespace = toNamespace
+		if err := waitForInstance(cmd, util.BlackDuckName, cloneToName); err != nil {
+			return err
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(cloneCmd)
+
+	cloneBlackDuckCmd.Flags().StringVarP(&namespace, "namespace", "n", namespace, "Namespace of the instance")
+	cobra.MarkFlagRequired(cloneBlackDuckCmd.Flags(), "namespace")
+	cloneBlackDuckCmd.Flags().StringVar(&cloneToName, "to", cloneToName, "Name of the new instance")
+	cobra.MarkFlagRequired(cloneBlackDuckCmd.Flags(), "to")
+	cloneBlackDuckCmd.Flags().StringVar(&cloneToNamespace, "to-namespace", cloneToNamespace, "Namespace of the new instance, it's created if it doesn't exist (default the namespace of the instance)")
+	addChartLocationPathFlag(cloneBlackDuckCmd)
+	addWaitFlags(cloneBlackDuckCmd)
+	cloneCmd.AddCommand(cloneBlackDuckCmd)
+}
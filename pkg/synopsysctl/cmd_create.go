@@ -88,6 +88,9 @@ var createAlertCmd = &cobra.Command{
 		return nil
 	},
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := setVersionFromBundle(cmd.Flags()); err != nil {
+			return err
+		}
 		alertName := args[0]
 		helmReleaseName := fmt.Sprintf("%s%s", alertName, globals.AlertPostSuffix)
 
@@ -99,6 +102,13 @@ var createAlertCmd = &cobra.Command{
 			return fmt.Errorf("creation of Alert instance is only suported for version 5.3.1 and above")
 		}
 
+		// Set the values from the --values files, the flags take precedence over them
+		valuesFromFiles, err := mergeValuesFiles(cmd.Flags(), map[string]interface{}{})
+		if err != nil {
+			return err
+		}
+		createAlertCobraHelper.SetArgs(valuesFromFiles)
+
 		// Get the flags to set Helm values
 		helmValuesMap, err := createAlertCobraHelper.GenerateHelmFlagsFromCobraFlags(cmd.Flags())
 		if err != nil {
@@ -171,6 +181,10 @@ var createAlertCmd = &cobra.Command{
 			return fmt.Errorf("failed to create Alert resources: %+v", cleanErrorMsg)
 		}
 
+		if err := waitForInstance(cmd, util.AlertName, alertName); err != nil {
+			return err
+		}
+
 		log.Infof("Alert has been successfully Created!")
 		return nil
 	},
@@ -192,6 +206,9 @@ var createAlertNativeCmd = &cobra.Command{
 		return nil
 	},
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := setVersionFromBundle(cmd.Flags()); err != nil {
+			return err
+		}
 		alertName := args[0]
 		helmReleaseName := fmt.Sprintf("%s%s", alertName, globals.AlertPostSuffix)
 
@@ -203,6 +220,13 @@ var createAlertNativeCmd = &cobra.Command{
 			return fmt.Errorf("creation of Alert instance is only suported for version 5.3.1 and above")
 		}
 
+		// Set the values from the --values files, the flags take precedence over them
+		valuesFromFiles, err := mergeValuesFiles(cmd.Flags(), map[string]interface{}{})
+		if err != nil {
+			return err
+		}
+		createAlertCobraHelper.SetArgs(valuesFromFiles)
+
 		// Get the flags to set Helm values
 		helmValuesMap, err := createAlertCobraHelper.GenerateHelmFlagsFromCobraFlags(cmd.Flags())
 		if err != nil {
@@ -325,6 +349,9 @@ var createBlackDuckCmd = &cobra.Command{
 		return nil
 	},
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := setVersionFromBundle(cmd.Flags()); err != nil {
+			return err
+		}
 		ok, err := util.IsVersionGreaterThanOrEqualTo(cmd.Flag("version").Value.String(), 2020, time.April, 0)
 		if err != nil {
 			return err
@@ -333,6 +360,13 @@ var createBlackDuckCmd = &cobra.Command{
 			return fmt.Errorf("creation of Black Duck instance is only suported for version 2020.4.0 and above")
 		}
 
+		// Set the values from the --values files, the flags take precedence over them
+		valuesFromFiles, err := mergeValuesFiles(cmd.Flags(), map[string]interface{}{})
+		if err != nil {
+			return err
+		}
+		createBlackDuckCobraHelper.SetArgs(valuesFromFiles)
+
 		helmValuesMap, err := createBlackDuckCobraHelper.GenerateHelmFlagsFromCobraFlags(cmd.Flags())
 		if err != nil {
 			return err
@@ -392,6 +426,10 @@ var createBlackDuckCmd = &cobra.Command{
 			return err
 		}
 
+		if err := waitForInstance(cmd, util.BlackDuckName, args[0]); err != nil {
+			return err
+		}
+
 		log.Infof("Black Duck has been successfully Created!")
 		return nil
 	},
@@ -419,6 +457,9 @@ var createBlackDuckNativeCmd = &cobra.Command{
 		return nil
 	},
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := setVersionFromBundle(cmd.Flags()); err != nil {
+			return err
+		}
 		ok, err := util.IsVersionGreaterThanOrEqualTo(cmd.Flag("version").Value.String(), 2020, time.April, 0)
 		if err != nil {
 			return err
@@ -427,6 +468,13 @@ var createBlackDuckNativeCmd = &cobra.Command{
 			return fmt.Errorf("creation of Black Duck instance is only suported for version 2020.4.0 and above")
 		}
 
+		// Set the values from the --values files, the flags take precedence over them
+		valuesFromFiles, err := mergeValuesFiles(cmd.Flags(), map[string]interface{}{})
+		if err != nil {
+			return err
+		}
+		createBlackDuckCobraHelper.SetArgs(valuesFromFiles)
+
 		helmValuesMap, err := createBlackDuckCobraHelper.GenerateHelmFlagsFromCobraFlags(cmd.Flags())
 		if err != nil {
 			return err
@@ -504,8 +552,18 @@ var createOpsSightCmd = &cobra.Command{
 		return nil
 	},
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := setVersionFromBundle(cmd.Flags()); err != nil {
+			return err
+		}
 		opssightName := args[0]
 
+		// Set the values from the --values files, the flags take precedence over them
+		valuesFromFiles, err := mergeValuesFiles(cmd.Flags(), map[string]interface{}{})
+		if err != nil {
+			return err
+		}
+		createOpsSightCobraHelper.SetArgs(valuesFromFiles)
+
 		// Get the flags to set Helm values
 		helmValuesMap, err := createOpsSightCobraHelper.GenerateHelmFlagsFromCobraFlags(cmd.Flags())
 		if err != nil {
@@ -538,6 +596,10 @@ var createOpsSightCmd = &cobra.Command{
 			return fmt.Errorf("failed to create OpsSight resources: %+v", err)
 		}
 
+		if err := waitForInstance(cmd, util.OpsSightName, opssightName); err != nil {
+			return err
+		}
+
 		log.Infof("OpsSight has been successfully Created!")
 		return nil
 	},
@@ -559,8 +621,18 @@ var createOpsSightNativeCmd = &cobra.Command{
 		return nil
 	},
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := setVersionFromBundle(cmd.Flags()); err != nil {
+			return err
+		}
 		opssightName := args[0]
 
+		// Set the values from the --values files, the flags take precedence over them
+		valuesFromFiles, err := mergeValuesFiles(cmd.Flags(), map[string]interface{}{})
+		if err != nil {
+			return err
+		}
+		createOpsSightCobraHelper.SetArgs(valuesFromFiles)
+
 		// Get the flags to set Helm values
 		helmValuesMap, err := createOpsSightCobraHelper.GenerateHelmFlagsFromCobraFlags(cmd.Flags())
 		if err != nil {
@@ -607,6 +679,16 @@ var createPolarisCmd = &cobra.Command{
 		return nil
 	},
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := setVersionFromBundle(cmd.Flags()); err != nil {
+			return err
+		}
+		// Set the values from the --values files, the flags take precedence over them
+		valuesFromFiles, err := mergeValuesFiles(cmd.Flags(), map[string]interface{}{})
+		if err != nil {
+			return err
+		}
+		createPolarisCobraHelper.SetArgs(valuesFromFiles)
+
 		// Get the flags to set Helm values
 		helmValuesMap, err := createPolarisCobraHelper.GenerateHelmFlagsFromCobraFlags(cmd.Flags())
 		if err != nil {
@@ -639,6 +721,10 @@ var createPolarisCmd = &cobra.Command{
 			return fmt.Errorf("failed to create Polaris resources: %+v", err)
 		}
 
+		if err := waitForInstance(cmd, globals.PolarisName, globals.PolarisName); err != nil {
+			return err
+		}
+
 		log.Infof("Polaris has been successfully Created!")
 		return nil
 	},
@@ -660,6 +746,16 @@ var createPolarisNativeCmd = &cobra.Command{
 		return nil
 	},
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := setVersionFromBundle(cmd.Flags()); err != nil {
+			return err
+		}
+		// Set the values from the --values files, the flags take precedence over them
+		valuesFromFiles, err := mergeValuesFiles(cmd.Flags(), map[string]interface{}{})
+		if err != nil {
+			return err
+		}
+		createPolarisCobraHelper.SetArgs(valuesFromFiles)
+
 		// Get the flags to set Helm values
 		helmValuesMap, err := createPolarisCobraHelper.GenerateHelmFlagsFromCobraFlags(cmd.Flags())
 		if err != nil {
@@ -728,6 +824,13 @@ var createPolarisReportingCmd = &cobra.Command{
 		return nil
 	},
 	RunE: func(cmd *cobra.Command, args []string) error {
+		// Set the values from the --values files, the flags take precedence over them
+		valuesFromFiles, err := mergeValuesFiles(cmd.Flags(), map[string]interface{}{})
+		if err != nil {
+			return err
+		}
+		createPolarisReportingCobraHelper.SetArgs(valuesFromFiles)
+
 		// Get the flags to set Helm values
 		helmValuesMap, err := createPolarisReportingCobraHelper.GenerateHelmFlagsFromCobraFlags(cmd.Flags())
 		if err != nil {
@@ -760,6 +863,10 @@ var createPolarisReportingCmd = &cobra.Command{
 			return fmt.Errorf("failed to create Polaris-Reporting resources: %+v", err)
 		}
 
+		if err := waitForInstance(cmd, globals.PolarisReportingName, globals.PolarisReportingName); err != nil {
+			return err
+		}
+
 		log.Infof("Polaris-Reporting has been successfully Created!")
 		return nil
 	},
@@ -781,6 +888,13 @@ var createPolarisReportingNativeCmd = &cobra.Command{
 		return nil
 	},
 	RunE: func(cmd *cobra.Command, args []string) error {
+		// Set the values from the --values files, the flags take precedence over them
+		valuesFromFiles, err := mergeValuesFiles(cmd.Flags(), map[string]interface{}{})
+		if err != nil {
+			return err
+		}
+		createPolarisReportingCobraHelper.SetArgs(valuesFromFiles)
+
 		// Get the flags to set Helm values
 		helmValuesMap, err := createPolarisReportingCobraHelper.GenerateHelmFlagsFromCobraFlags(cmd.Flags())
 		if err != nil {
@@ -827,6 +941,16 @@ var createBDBACmd = &cobra.Command{
 		return nil
 	},
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := setVersionFromBundle(cmd.Flags()); err != nil {
+			return err
+		}
+		// Set the values from the --values files, the flags take precedence over them
+		valuesFromFiles, err := mergeValuesFiles(cmd.Flags(), map[string]interface{}{})
+		if err != nil {
+			return err
+		}
+		createBDBACobraHelper.SetArgs(valuesFromFiles)
+
 		// Get the flags to set Helm values
 		helmValuesMap, err := createBDBACobraHelper.GenerateHelmFlagsFromCobraFlags(cmd.Flags())
 		if err != nil {
@@ -859,6 +983,10 @@ var createBDBACmd = &cobra.Command{
 			return fmt.Errorf("failed to create BDBA resources: %+v", err)
 		}
 
+		if err := waitForInstance(cmd, globals.BDBAName, globals.BDBAName); err != nil {
+			return err
+		}
+
 		log.Infof("BDBA has been successfully Created!")
 		return nil
 	},
@@ -880,6 +1008,16 @@ var createBDBANativeCmd = &cobra.Command{
 		return nil
 	},
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := setVersionFromBundle(cmd.Flags()); err != nil {
+			return err
+		}
+		// Set the values from the --values files, the flags take precedence over them
+		valuesFromFiles, err := mergeValuesFiles(cmd.Flags(), map[string]interface{}{})
+		if err != nil {
+			return err
+		}
+		createBDBACobraHelper.SetArgs(valuesFromFiles)
+
 		// Get the flags to set Helm values
 		helmValuesMap, err := createBDBACobraHelper.GenerateHelmFlagsFromCobraFlags(cmd.Flags())
 		if err != nil {
@@ -926,33 +1064,48 @@ func init() {
 	cobra.MarkFlagRequired(createAlertCmd.PersistentFlags(), "namespace")
 	createAlertCobraHelper.AddCobraFlagsToCommand(createAlertCmd, true)
 	addChartLocationPathFlag(createAlertCmd)
+	addValuesFlag(createAlertCmd)
+	addWaitFlags(createAlertCmd)
+	addBundleFlag(createAlertCmd)
 	createCmd.AddCommand(createAlertCmd)
 
 	createAlertCobraHelper.AddCobraFlagsToCommand(createAlertNativeCmd, true)
 	addChartLocationPathFlag(createAlertNativeCmd)
+	addValuesFlag(createAlertNativeCmd)
+	addBundleFlag(createAlertNativeCmd)
 	createAlertCmd.AddCommand(createAlertNativeCmd)
 
 	// Add Black Duck Command
 	createBlackDuckCmd.PersistentFlags().StringVarP(&namespace, "namespace", "n", namespace, "Namespace of the instance(s)")
 	cobra.MarkFlagRequired(createBlackDuckCmd.PersistentFlags(), "namespace")
 	addChartLocationPathFlag(createBlackDuckCmd)
+	addValuesFlag(createBlackDuckCmd)
+	addWaitFlags(createBlackDuckCmd)
+	addBundleFlag(createBlackDuckCmd)
 	createBlackDuckCobraHelper.AddCRSpecFlagsToCommand(createBlackDuckCmd, true)
 	createCmd.AddCommand(createBlackDuckCmd)
 
 	createBlackDuckCobraHelper.AddCRSpecFlagsToCommand(createBlackDuckNativeCmd, true)
 	addNativeFlags(createBlackDuckNativeCmd)
 	addChartLocationPathFlag(createBlackDuckNativeCmd)
+	addValuesFlag(createBlackDuckNativeCmd)
+	addBundleFlag(createBlackDuckNativeCmd)
 	createBlackDuckCmd.AddCommand(createBlackDuckNativeCmd)
 
 	// Add OpsSight Command
 	createOpsSightCmd.PersistentFlags().StringVarP(&namespace, "namespace", "n", namespace, "Namespace of the instance(s)")
 	cobra.MarkFlagRequired(createOpsSightCmd.PersistentFlags(), "namespace")
 	addChartLocationPathFlag(createOpsSightCmd)
+	addValuesFlag(createOpsSightCmd)
+	addWaitFlags(createOpsSightCmd)
+	addBundleFlag(createOpsSightCmd)
 	createOpsSightCobraHelper.AddCobraFlagsToCommand(createOpsSightCmd, true)
 	createCmd.AddCommand(createOpsSightCmd)
 
 	createOpsSightCobraHelper.AddCobraFlagsToCommand(createOpsSightNativeCmd, true)
 	addChartLocationPathFlag(createOpsSightNativeCmd)
+	addValuesFlag(createOpsSightNativeCmd)
+	addBundleFlag(createOpsSightNativeCmd)
 	createOpsSightCmd.AddCommand(createOpsSightNativeCmd)
 
 	// Add Polaris commands
@@ -960,10 +1113,15 @@ func init() {
 	cobra.MarkFlagRequired(createPolarisCmd.PersistentFlags(), "namespace")
 	createPolarisCobraHelper.AddCobraFlagsToCommand(createPolarisCmd, true)
 	addChartLocationPathFlag(createPolarisCmd)
+	addValuesFlag(createPolarisCmd)
+	addWaitFlags(createPolarisCmd)
+	addBundleFlag(createPolarisCmd)
 	createCmd.AddCommand(createPolarisCmd)
 
 	createPolarisCobraHelper.AddCobraFlagsToCommand(createPolarisNativeCmd, true)
 	addChartLocationPathFlag(createPolarisNativeCmd)
+	addValuesFlag(createPolarisNativeCmd)
+	addBundleFlag(createPolarisNativeCmd)
 	createPolarisCmd.AddCommand(createPolarisNativeCmd)
 
 	// Add Polaris-Reporting commands
@@ -971,10 +1129,13 @@ func init() {
 	cobra.MarkFlagRequired(createPolarisReportingCmd.PersistentFlags(), "namespace")
 	createPolarisReportingCobraHelper.AddCobraFlagsToCommand(createPolarisReportingCmd, true)
 	addChartLocationPathFlag(createPolarisReportingCmd)
+	addValuesFlag(createPolarisReportingCmd)
+	addWaitFlags(createPolarisReportingCmd)
 	createCmd.AddCommand(createPolarisReportingCmd)
 
 	createPolarisReportingCobraHelper.AddCobraFlagsToCommand(createPolarisReportingNativeCmd, true)
 	addChartLocationPathFlag(createPolarisReportingNativeCmd)
+	addValuesFlag(createPolarisReportingNativeCmd)
 	createPolarisReportingCmd.AddCommand(createPolarisReportingNativeCmd)
 
 	// Add BDBA commands
@@ -982,10 +1143,15 @@ func init() {
 	cobra.MarkFlagRequired(createBDBACmd.PersistentFlags(), "namespace")
 	createBDBACobraHelper.AddCobraFlagsToCommand(createBDBACmd, true)
 	addChartLocationPathFlag(createBDBACmd)
+	addValuesFlag(createBDBACmd)
+	addWaitFlags(createBDBACmd)
+	addBundleFlag(createBDBACmd)
 	createCmd.AddCommand(createBDBACmd)
 
 	createBDBACobraHelper.AddCobraFlagsToCommand(createBDBANativeCmd, true)
 	addChartLocationPathFlag(createBDBANativeCmd)
+	addValuesFlag(createBDBANativeCmd)
+	addBundleFlag(createBDBANativeCmd)
 	createBDBACmd.AddCommand(createBDBANativeCmd)
 
 }
@@ -59,6 +59,10 @@ var deleteAlertCmd = &cobra.Command{
 		alertName := args[0]
 		helmReleaseName := fmt.Sprintf("%s%s", alertName, globals.AlertPostSuffix)
 
+		if err := snapshotInstance(cmd, util.AlertName, alertName); err != nil {
+			return err
+		}
+
 		// Delete the Secrets
 		helmRelease, err := util.GetWithHelm3(helmReleaseName, namespace, kubeConfigPath)
 		if err != nil {
@@ -85,6 +89,9 @@ var deleteAlertCmd = &cobra.Command{
 			cleanErrorMsg := cleanAlertHelmError(err.Error(), helmReleaseName, alertName)
 			return fmt.Errorf("failed to delete Alert resources: %+v", cleanErrorMsg)
 		}
+		if err := util.DeleteSecretRevisions(kubeClient, namespace, helmReleaseName); err != nil {
+			return fmt.Errorf("failed to delete the saved revisions of the Alert secrets: %+v", err)
+		}
 
 		labelSelector := fmt.Sprintf("app=%s, name=%s", util.AlertName, alertName)
 		svcs, err := util.ListServices(kubeClient, namespace, labelSelector)
@@ -103,6 +110,10 @@ var deleteAlertCmd = &cobra.Command{
 			return err
 		}
 
+		if _, err := util.DeleteSchedules(kubeClient, namespace, util.AlertName, alertName); err != nil {
+			return fmt.Errorf("failed to delete the schedules of Alert: %+v", err)
+		}
+
 		log.Infof("Alert has been successfully Deleted!")
 		return nil
 	},
@@ -123,6 +134,10 @@ var deleteBlackDuckCmd = &cobra.Command{
 		return nil
 	},
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := snapshotInstance(cmd, util.BlackDuckName, args[0]); err != nil {
+			return err
+		}
+
 		err := util.DeleteWithHelm3(args[0], namespace, kubeConfigPath)
 		if err != nil {
 			return fmt.Errorf("failed to delete Blackduck resources: %+v", err)
@@ -135,6 +150,9 @@ var deleteBlackDuckCmd = &cobra.Command{
 				return fmt.Errorf("couldn't delete secret '%s' in namespace '%s' due to %+v", v, namespace, err)
 			}
 		}
+		if err := util.DeleteSecretRevisions(kubeClient, namespace, args[0]); err != nil {
+			return fmt.Errorf("couldn't delete the saved revisions of the secrets in namespace '%s' due to %+v", namespace, err)
+		}
 
 		labelSelector := fmt.Sprintf("app=%s, name=%s", util.BlackDuckName, args[0])
 		// delete exposed service
@@ -168,6 +186,10 @@ var deleteBlackDuckCmd = &cobra.Command{
 			}
 		}
 
+		if _, err := util.DeleteSchedules(kubeClient, namespace, util.BlackDuckName, args[0]); err != nil {
+			return fmt.Errorf("failed to delete the schedules of Black Duck: %+v", err)
+		}
+
 		log.Infof("Black Duck has been successfully Deleted!")
 		return nil
 	},
@@ -191,12 +213,20 @@ var deleteOpsSightCmd = &cobra.Command{
 		opssightName := args[0]
 		// TODO Delete any initial resources...
 
+		if err := snapshotInstance(cmd, util.OpsSightName, opssightName); err != nil {
+			return err
+		}
+
 		// Delete Opssight Resources
 		err := util.DeleteWithHelm3(opssightName, namespace, kubeConfigPath)
 		if err != nil {
 			return fmt.Errorf("failed to delete OpsSight resources: %+v", err)
 		}
 
+		if _, err := util.DeleteSchedules(kubeClient, namespace, util.OpsSightName, opssightName); err != nil {
+			return fmt.Errorf("failed to delete the schedules of OpsSight: %+v", err)
+		}
+
 		log.Infof("OpsSight has been successfully Deleted!")
 		return nil
 	},
@@ -218,6 +248,10 @@ var deletePolarisCmd = &cobra.Command{
 		return nil
 	},
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := snapshotInstance(cmd, globals.PolarisName, globals.PolarisName); err != nil {
+			return err
+		}
+
 		// Delete Polaris Resources
 		err := util.DeleteWithHelm3(globals.PolarisName, namespace, kubeConfigPath)
 		if err != nil {
@@ -232,6 +266,10 @@ var deletePolarisCmd = &cobra.Command{
 			return err
 		}
 
+		if _, err := util.DeleteSchedules(kubeClient, namespace, globals.PolarisName, globals.PolarisName); err != nil {
+			return fmt.Errorf("failed to delete the schedules of Polaris: %+v", err)
+		}
+
 		log.Infof("Polaris has been successfully Deleted!")
 		return nil
 	},
@@ -253,6 +291,10 @@ var deletePolarisReportingCmd = &cobra.Command{
 		return nil
 	},
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := snapshotInstance(cmd, globals.PolarisReportingName, globals.PolarisReportingName); err != nil {
+			return err
+		}
+
 		// Delete Polaris-Reporting Resources
 		err := util.DeleteWithHelm3(globals.PolarisReportingName, namespace, kubeConfigPath)
 		if err != nil {
@@ -267,6 +309,10 @@ var deletePolarisReportingCmd = &cobra.Command{
 			return err
 		}
 
+		if _, err := util.DeleteSchedules(kubeClient, namespace, globals.PolarisReportingName, globals.PolarisReportingName); err != nil {
+			return fmt.Errorf("failed to delete the schedules of Polaris-Reporting: %+v", err)
+		}
+
 		log.Infof("Polaris-Reporting has been successfully Deleted!")
 		return nil
 	},
@@ -316,12 +362,20 @@ var deleteBDBACmd = &cobra.Command{
 		return nil
 	},
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := snapshotInstance(cmd, globals.BDBAName, globals.BDBAName); err != nil {
+			return err
+		}
+
 		// Delete Resources
 		err := util.DeleteWithHelm3(globals.BDBAName, namespace, kubeConfigPath)
 		if err != nil {
 			return fmt.Errorf("failed to delete BDBA resources: %+v", err)
 		}
 
+		if _, err := util.DeleteSchedules(kubeClient, namespace, globals.BDBAName, globals.BDBAName); err != nil {
+			return fmt.Errorf("failed to delete the schedules of BDBA: %+v", err)
+		}
+
 		log.Infof("BDBA has been successfully Deleted!")
 		return nil
 	},
@@ -336,30 +390,36 @@ func init() {
 	// Add Delete Alert Command
 	deleteAlertCmd.Flags().StringVarP(&namespace, "namespace", "n", namespace, "Namespace of the instance(s)")
 	cobra.MarkFlagRequired(deleteAlertCmd.Flags(), "namespace")
+	addSnapshotFlags(deleteAlertCmd)
 	deleteCmd.AddCommand(deleteAlertCmd)
 
 	// Add Delete Black Duck Command
 	deleteBlackDuckCmd.Flags().StringVarP(&namespace, "namespace", "n", namespace, "Namespace of the instance(s)")
 	cobra.MarkFlagRequired(deleteBlackDuckCmd.Flags(), "namespace")
+	addSnapshotFlags(deleteBlackDuckCmd)
 	deleteCmd.AddCommand(deleteBlackDuckCmd)
 
 	// Add Delete OpsSight Command
 	deleteOpsSightCmd.Flags().StringVarP(&namespace, "namespace", "n", namespace, "Namespace of the instance(s)")
 	cobra.MarkFlagRequired(deleteOpsSightCmd.Flags(), "namespace")
+	addSnapshotFlags(deleteOpsSightCmd)
 	deleteCmd.AddCommand(deleteOpsSightCmd)
 
 	// Add Delete Polaris Command
 	deletePolarisCmd.Flags().StringVarP(&namespace, "namespace", "n", namespace, "Namespace of the instance(s)")
 	cobra.MarkFlagRequired(deletePolarisCmd.Flags(), "namespace")
+	addSnapshotFlags(deletePolarisCmd)
 	deleteCmd.AddCommand(deletePolarisCmd)
 
 	// Add Delete Polaris-Reporting Command
 	deletePolarisReportingCmd.Flags().StringVarP(&namespace, "namespace", "n", namespace, "Namespace of the instance(s)")
 	cobra.MarkFlagRequired(deletePolarisReportingCmd.Flags(), "namespace")
+	addSnapshotFlags(deletePolarisReportingCmd)
 	deleteCmd.AddCommand(deletePolarisReportingCmd)
 
 	// Add Delete BDBA Command
 	deleteBDBACmd.Flags().StringVarP(&namespace, "namespace", "n", namespace, "Namespace of the instance(s)")
 	cobra.MarkFlagRequired(deleteBDBACmd.Flags(), "namespace")
+	addSnapshotFlags(deleteBDBACmd)
 	deleteCmd.AddCommand(deleteBDBACmd)
 }
@@ -27,15 +27,21 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/blackducksoftware/synopsysctl/pkg/globals"
 	"github.com/blackducksoftware/synopsysctl/pkg/util"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	"helm.sh/helm/v3/pkg/release"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/yaml"
 )
 
 // Get Command flag for -output functionality
-var getOutputFormat string
+var getOutputFormat = util.OutputTable
 
 // Get Command flag for -selector functionality
 var getSelector string
@@ -43,26 +49,165 @@ var getSelector string
 // Get Command flag for --all-namespaces functionality
 var getAllNamespaces bool
 
-func generateKubectlGetCommand(resourceName string, args []string) []string {
-	kubectlCmd := []string{"get", resourceName}
-	if len(namespace) > 0 {
-		kubectlCmd = append(kubectlCmd, "-n", namespace)
+// Get Command flag for --export functionality
+var getExport bool
+
+// printExportedValues prints the values of an instance without its secrets as a values file for 'create -f'
+func printExportedValues(product, name string, values map[string]interface{}) error {
+	exportedValues, redactedKeys := util.RedactHelmValues(values)
+	valuesBytes, err := yaml.Marshal(exportedValues)
+	if err != nil {
+		return fmt.Errorf("failed to convert the values of %s '%s' to YAML due to %+v", product, name, err)
+	}
+	fmt.Printf("# Values of %s '%s' in namespace '%s', use them with 'synopsysctl create %s -f <file>'\n", product, name, namespace, product)
+	if len(redactedKeys) > 0 {
+		fmt.Printf("# The following secrets were removed, set them with flags or another values file:\n")
+		for _, key := range redactedKeys {
+			fmt.Printf("#   %s\n", key)
+		}
+	}
+	fmt.Printf("%s", valuesBytes)
+	return nil
+}
+
+// instanceSummary is an instance of a product listed by the get commands
+type instanceSummary struct {
+	Product      string `json:"product"`
+	Name         string `json:"name"`
+	Namespace    string `json:"namespace"`
+	Version      string `json:"version"`
+	State        string `json:"state"`
+	ChartVersion string `json:"chartVersion"`
+	Revision     int    `json:"revision"`
+	Updated      string `json:"updated"`
+}
+
+// getInstanceSummary returns the summary of the instance of a product in a release
+func getInstanceSummary(product string, helmRelease *release.Release) instanceSummary {
+	name := helmRelease.Name
+	if product == util.AlertName {
+		name = strings.TrimSuffix(name, globals.AlertPostSuffix)
+	}
+	_, versionKey := getReleaseNameAndVersionKey(product, name)
+	instance := instanceSummary{
+		Product:      product,
+		Name:         name,
+		Namespace:    helmRelease.Namespace,
+		Version:      getReleaseAppVersion(helmRelease, versionKey),
+		ChartVersion: helmRelease.Chart.Metadata.Version,
+		Revision:     helmRelease.Version,
+	}
+	if helmRelease.Info != nil {
+		instance.Updated = helmRelease.Info.LastDeployed.Format("2006-01-02 15:04:05 MST")
+	}
+	instance.State = getReleaseState(product, helmRelease, util.GetReleaseValues(helmRelease))
+	return instance
+}
+
+// getReleaseState returns whether the instance of a product in a release is Running or Stopped. Alert, Black Duck and OpsSight
+// are stopped with their status value, the other products, and the scheduled stops of all products, scale their workloads
+// to 0 with an annotation
+func getReleaseState(product string, helmRelease *release.Release, values map[string]interface{}) string {
+	if state, ok := values["status"].(string); ok && len(state) > 0 && state != "Running" {
+		return state
 	}
-	if len(args) > 0 {
-		kubectlCmd = append(kubectlCmd, args...)
+	stopped, err := util.AreReleaseWorkloadsStopped(kubeClient, helmRelease.Namespace, helmRelease.Manifest)
+	if err != nil {
+		log.Debugf("unable to get the state of %s in namespace '%s' due to %+v", product, helmRelease.Namespace, err)
 	}
-	if getOutputFormat != "" {
-		kubectlCmd = append(kubectlCmd, "-o")
-		kubectlCmd = append(kubectlCmd, getOutputFormat)
+	if stopped {
+		return "Stopped"
 	}
-	if getSelector != "" {
-		kubectlCmd = append(kubectlCmd, "-l")
-		kubectlCmd = append(kubectlCmd, getSelector)
+	return "Running"
+}
+
+// getInstancesTable returns the table of the instances, the product column is only shown for several products
+func getInstancesTable(showProduct bool, instances []instanceSummary) util.Table {
+	table := util.Table{Columns: []util.TableColumn{{Header: "NAME"}, {Header: "NAMESPACE"}, {Header: "VERSION"}, {Header: "STATE"}, {Header: "CHART VERSION"}, {Header: "REVISION", Wide: true}, {Header: "UPDATED", Wide: true}}}
+	if showProduct {
+		table.Columns = append([]util.TableColumn{{Header: "PRODUCT"}}, table.Columns...)
+	}
+	for _, instance := range instances {
+		row := []string{instance.Name, instance.Namespace, instance.Version, instance.State, instance.ChartVersion, strconv.Itoa(instance.Revision), instance.Updated}
+		if showProduct {
+			row = append([]string{instance.Product}, row...)
+		}
+		table.Rows = append(table.Rows, row)
 	}
+	return table
+}
+
+// listInstances returns the instances of the products in the namespace, or in all namespaces with --all-namespaces,
+// that match the --selector. The selector can use the app (product), name, version and state of the instances
+func listInstances(products []string) ([]instanceSummary, error) {
+	listNamespace := namespace
 	if getAllNamespaces {
-		kubectlCmd = append(kubectlCmd, globals.AllNamespacesFlag)
+		listNamespace = ""
+	} else if len(namespace) == 0 {
+		return nil, fmt.Errorf("a namespace is required, use --namespace or --all-namespaces")
+	}
+	selector, err := labels.Parse(getSelector)
+	if err != nil {
+		return nil, fmt.Errorf("'%s' is an invalid selector due to %+v", getSelector, err)
+	}
+	releases, err := util.ListWithHelm3(listNamespace, kubeConfigPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the instances due to %+v", err)
 	}
-	return kubectlCmd
+
+	instances := []instanceSummary{}
+	for _, product := range products {
+		chartName, err := getChartName(product)
+		if err != nil {
+			return nil, err
+		}
+		productInstances := []instanceSummary{}
+		for _, helmRelease := range releases {
+			if helmRelease.Chart == nil || helmRelease.Chart.Metadata == nil || helmRelease.Chart.Metadata.Name != chartName {
+				continue
+			}
+			instance := getInstanceSummary(product, helmRelease)
+			if selector.Matches(labels.Set{"app": product, "name": instance.Name, "version": instance.Version, "state": instance.State}) {
+				productInstances = append(productInstances, instance)
+			}
+		}
+		sort.Slice(productInstances, func(i, j int) bool {
+			if productInstances[i].Namespace != productInstances[j].Namespace {
+				return productInstances[i].Namespace < productInstances[j].Namespace
+			}
+			return productInstances[i].Name < productInstances[j].Name
+		})
+		instances = append(instances, productInstances...)
+	}
+	return instances, nil
+}
+
+// printInstances lists the instances of the products in the format of the --output flag
+func printInstances(cmd *cobra.Command, products []string) error {
+	instances, err := listInstances(products)
+	if err != nil {
+		return err
+	}
+	if len(instances) == 0 && isTableOutput(cmd) {
+		log.Infof("no instances found")
+		return nil
+	}
+	return printOutput(cmd, instances, getInstancesTable(len(products) > 1, instances))
+}
+
+// printInstance prints the values of an instance, or its summary in the table and wide formats. The values
+// are printed as YAML if the --output flag isn't set
+func printInstance(cmd *cobra.Command, product string, helmRelease *release.Release) error {
+	if !cmd.Flags().Changed("output") {
+		return util.PrintOutput(os.Stdout, util.OutputYAML, helmRelease.Config)
+	}
+	return printOutput(cmd, helmRelease.Config, getInstancesTable(false, []instanceSummary{getInstanceSummary(product, helmRelease)}))
+}
+
+// isListingInstances returns true if a get command of a product without a NAME should list its instances
+// rather than display the values of the instance in the namespace
+func isListingInstances() bool {
+	return getAllNamespaces || len(getSelector) > 0
 }
 
 // getCmd lists resources in the cluster
@@ -74,21 +219,46 @@ var getCmd = &cobra.Command{
 	},
 }
 
+// getAllCmd lists the instances of all products
+var getAllCmd = &cobra.Command{
+	Use:           "all -n NAMESPACE",
+	Example:       "synopsysctl get all -n <namespace>\nsynopsysctl get all --all-namespaces\nsynopsysctl get all --all-namespaces -l state=Stopped",
+	Short:         "List the instances of all Synopsys products",
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 0 {
+			cmd.Help()
+			return fmt.Errorf("this command takes 0 arguments, but got %+v", args)
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return printInstances(cmd, versionsProducts)
+	},
+}
+
 // getAlertCmd display one or many Alert instances
 var getAlertCmd = &cobra.Command{
-	Use:           "alert NAME -n NAMESPACE",
-	Example:       "synopsysctl get alert <name> -n <namespace>",
+	Use:           "alert [NAME] -n NAMESPACE",
+	Example:       "synopsysctl get alert <name> -n <namespace>\nsynopsysctl get alert -n <namespace>\nsynopsysctl get alert --all-namespaces",
 	Aliases:       []string{"alerts"},
-	Short:         "Display an Alert instance",
+	Short:         "Display an Alert instance or list the Alert instances",
 	SilenceUsage:  true,
 	SilenceErrors: true,
 	Args: func(cmd *cobra.Command, args []string) error {
-		if len(args) != 1 {
-			return fmt.Errorf("this command takes 1 argument, but got %+v", args)
+		if len(args) > 1 {
+			return fmt.Errorf("this command takes up to 1 argument, but got %+v", args)
 		}
 		return nil
 	},
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			return printInstances(cmd, []string{util.AlertName})
+		}
+		if len(namespace) == 0 {
+			return fmt.Errorf("a namespace is required to display an instance")
+		}
 		alertName := args[0]
 		helmReleaseName := fmt.Sprintf("%s%s", alertName, globals.AlertPostSuffix)
 		helmRelease, err := util.GetWithHelm3(helmReleaseName, namespace, kubeConfigPath)
@@ -96,35 +266,43 @@ var getAlertCmd = &cobra.Command{
 			cleanErrorMsg := cleanAlertHelmError(err.Error(), helmReleaseName, alertName)
 			return fmt.Errorf("failed to get values for Alert: %+v", cleanErrorMsg)
 		}
-		helmSetValues := helmRelease.Config
-		PrintComponent(helmSetValues, "YAML")
-		return nil
+		if getExport {
+			return printExportedValues(util.AlertName, alertName, helmRelease.Config)
+		}
+		return printInstance(cmd, util.AlertName, helmRelease)
 	},
 }
 
-// getBlackDuckCmd display a Black Duck instances
+// getBlackDuckCmd display one or many Black Duck instances
 var getBlackDuckCmd = &cobra.Command{
-	Use:           "blackduck NAME -n NAMESPACE",
-	Example:       "synopsysctl get blackduck <name> -n <namespace>",
+	Use:           "blackduck [NAME] -n NAMESPACE",
+	Example:       "synopsysctl get blackduck <name> -n <namespace>\nsynopsysctl get blackduck -n <namespace>\nsynopsysctl get blackduck --all-namespaces -l version=2020.4.0",
 	Aliases:       []string{"blackducks"},
-	Short:         "Display a Black Duck instance",
+	Short:         "Display a Black Duck instance or list the Black Duck instances",
 	SilenceUsage:  true,
 	SilenceErrors: true,
 	Args: func(cmd *cobra.Command, args []string) error {
-		if len(args) != 1 {
+		if len(args) > 1 {
 			cmd.Help()
-			return fmt.Errorf("this command takes 1 argument, but got %+v", args)
+			return fmt.Errorf("this command takes up to 1 argument, but got %+v", args)
 		}
 		return nil
 	},
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			return printInstances(cmd, []string{util.BlackDuckName})
+		}
+		if len(namespace) == 0 {
+			return fmt.Errorf("a namespace is required to display an instance")
+		}
 		helmRelease, err := util.GetWithHelm3(args[0], namespace, kubeConfigPath)
 		if err != nil {
 			return fmt.Errorf("failed to get Blackduck values: %+v", err)
 		}
-		helmSetValues := helmRelease.Config
-		PrintComponent(helmSetValues, "YAML")
-		return nil
+		if getExport {
+			return printExportedValues(util.BlackDuckName, args[0], helmRelease.Config)
+		}
+		return printInstance(cmd, util.BlackDuckName, helmRelease)
 	},
 }
 
@@ -143,16 +321,36 @@ var getBlackDuckRootKeyCmd = &cobra.Command{
 		return nil
 	},
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(namespace) == 0 {
+			return fmt.Errorf("a namespace is required to get the master key")
+		}
 		return getBlackDuckMasterKey(namespace, args[0], args[1])
 	},
 }
 
 // getBlackDuckMasterKey will retrieve the master key for the given Black Duck and store it in the file path
 func getBlackDuckMasterKey(namespace string, name string, filePath string) error {
+	_, masterKey, err := getBlackDuckSealAndMasterKey(namespace, name)
+	if err != nil {
+		return err
+	}
+
+	fileName := filepath.Join(filePath, fmt.Sprintf("%s-%s.key", namespace, name))
+	os.MkdirAll(filePath, os.ModePerm)
+	err = ioutil.WriteFile(fileName, []byte(masterKey), 0777)
+	if err != nil {
+		return fmt.Errorf("error writing to file '%s' due to %+v", fileName, err)
+	}
+	log.Infof("successfully retrieved the master key and stored it in '%s' file for Black Duck '%s' in namespace '%s'", fileName, name, namespace)
+	return nil
+}
+
+// getBlackDuckSealAndMasterKey will retrieve the seal key and the master key of the upload cache for the given Black Duck
+func getBlackDuckSealAndMasterKey(namespace string, name string) (string, string, error) {
 	// getting the seal key secret to retrieve the seal key
 	secret, err := util.GetSecret(kubeClient, namespace, fmt.Sprintf("%s-blackduck-upload-cache", name))
 	if err != nil {
-		return fmt.Errorf("unable to find Seal key secret (%s-blackduck-upload-cache) in namespace '%s' due to %+v", name, namespace, err)
+		return "", "", fmt.Errorf("unable to find Seal key secret (%s-blackduck-upload-cache) in namespace '%s' due to %+v", name, namespace, err)
 	}
 
 	sealKey := string(secret.Data["SEAL_KEY"])
@@ -160,7 +358,7 @@ func getBlackDuckMasterKey(namespace string, name string, filePath string) error
 	// Filter the upload cache pod to get the master key using the seal key
 	uploadCachePod, err := util.FilterPodByNamePrefixInNamespace(kubeClient, namespace, util.GetResourceName(name, util.BlackDuckName, "uploadcache"))
 	if err != nil {
-		return fmt.Errorf("unable to filter the upload cache pod in namespace '%s' due to %+v", namespace, err)
+		return "", "", fmt.Errorf("unable to filter the upload cache pod in namespace '%s' due to %+v", namespace, err)
 	}
 
 	// Create the exec into Kubernetes pod request
@@ -168,50 +366,49 @@ func getBlackDuckMasterKey(namespace string, name string, filePath string) error
 
 	stdout, err := util.ExecContainer(restconfig, req, []string{fmt.Sprintf(`curl -f --header "X-SEAL-KEY: %s" https://localhost:9444/api/internal/master-key --cert /opt/blackduck/hub/blackduck-upload-cache/security/blackduck-upload-cache-server.crt --key /opt/blackduck/hub/blackduck-upload-cache/security/blackduck-upload-cache-server.key --cacert /opt/blackduck/hub/blackduck-upload-cache/security/root.crt`, base64.StdEncoding.EncodeToString([]byte(sealKey)))})
 	if err != nil {
-		return fmt.Errorf("unable to exec into upload cache pod in namespace '%s' due to %+v", namespace, err)
+		return "", "", fmt.Errorf("unable to exec into upload cache pod in namespace '%s' due to %+v", namespace, err)
 	}
-
-	fileName := filepath.Join(filePath, fmt.Sprintf("%s-%s.key", namespace, name))
-	os.MkdirAll(filePath, os.ModePerm)
-	err = ioutil.WriteFile(fileName, []byte(stdout), 0777)
-	if err != nil {
-		return fmt.Errorf("error writing to file '%s' due to %+v", fileName, err)
-	}
-	log.Infof("successfully retrieved the master key and stored it in '%s' file for Black Duck '%s' in namespace '%s'", fileName, name, namespace)
-	return nil
+	return sealKey, stdout, nil
 }
 
-// getOpsSightCmd display an OpsSight instance
+// getOpsSightCmd display one or many OpsSight instances
 var getOpsSightCmd = &cobra.Command{
-	Use:           "opssight NAME -n NAMESPACE",
-	Example:       "synopsysctl get opssight -n <namespace>",
+	Use:           "opssight [NAME] -n NAMESPACE",
+	Example:       "synopsysctl get opssight <name> -n <namespace>\nsynopsysctl get opssight --all-namespaces",
 	Aliases:       []string{"opssights"},
-	Short:         "Display an OpsSight instance",
+	Short:         "Display an OpsSight instance or list the OpsSight instances",
 	SilenceUsage:  true,
 	SilenceErrors: true,
 	Args: func(cmd *cobra.Command, args []string) error {
-		if len(args) != 1 {
+		if len(args) > 1 {
 			cmd.Help()
-			return fmt.Errorf("this command takes 1 argument, but got %+v", args)
+			return fmt.Errorf("this command takes up to 1 argument, but got %+v", args)
 		}
 		return nil
 	},
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			return printInstances(cmd, []string{util.OpsSightName})
+		}
+		if len(namespace) == 0 {
+			return fmt.Errorf("a namespace is required to display an instance")
+		}
 		opssightName := args[0]
 		helmRelease, err := util.GetWithHelm3(opssightName, namespace, kubeConfigPath)
 		if err != nil {
 			return fmt.Errorf("failed to get OpsSight values: %+v", err)
 		}
-		helmSetValues := helmRelease.Config
-		PrintComponent(helmSetValues, "YAML")
-		return nil
+		if getExport {
+			return printExportedValues(util.OpsSightName, opssightName, helmRelease.Config)
+		}
+		return printInstance(cmd, util.OpsSightName, helmRelease)
 	},
 }
 
 // getPolarisCmd display the Polaris  instance
 var getPolarisCmd = &cobra.Command{
 	Use:           "polaris -n NAMESPACE",
-	Example:       "synopsysctl get polaris -n <namespace>",
+	Example:       "synopsysctl get polaris -n <namespace>\nsynopsysctl get polaris --all-namespaces",
 	Short:         "Display the polaris instance",
 	SilenceUsage:  true,
 	SilenceErrors: true,
@@ -222,20 +419,27 @@ var getPolarisCmd = &cobra.Command{
 		return nil
 	},
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if isListingInstances() {
+			return printInstances(cmd, []string{globals.PolarisName})
+		}
+		if len(namespace) == 0 {
+			return fmt.Errorf("a namespace is required, use --namespace or --all-namespaces")
+		}
 		helmRelease, err := util.GetWithHelm3(globals.PolarisName, namespace, kubeConfigPath)
 		if err != nil {
 			return fmt.Errorf("failed to get Polaris values: %+v", err)
 		}
-		helmSetValues := helmRelease.Config
-		PrintComponent(helmSetValues, "YAML")
-		return nil
+		if getExport {
+			return printExportedValues(globals.PolarisName, globals.PolarisName, helmRelease.Config)
+		}
+		return printInstance(cmd, globals.PolarisName, helmRelease)
 	},
 }
 
 // getPolarisReportingCmd display the Polaris Reporting instance
 var getPolarisReportingCmd = &cobra.Command{
 	Use:           "polaris-reporting -n NAMESPACE",
-	Example:       "synopsysctl get polaris-reporting -n <namespace>",
+	Example:       "synopsysctl get polaris-reporting -n <namespace>\nsynopsysctl get polaris-reporting --all-namespaces",
 	Short:         "Display the polaris-reporting instance",
 	SilenceUsage:  true,
 	SilenceErrors: true,
@@ -246,20 +450,27 @@ var getPolarisReportingCmd = &cobra.Command{
 		return nil
 	},
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if isListingInstances() {
+			return printInstances(cmd, []string{globals.PolarisReportingName})
+		}
+		if len(namespace) == 0 {
+			return fmt.Errorf("a namespace is required, use --namespace or --all-namespaces")
+		}
 		helmRelease, err := util.GetWithHelm3(globals.PolarisReportingName, namespace, kubeConfigPath)
 		if err != nil {
 			return fmt.Errorf("failed to get Polaris-Reporting values: %+v", err)
 		}
-		helmSetValues := helmRelease.Config
-		PrintComponent(helmSetValues, "YAML")
-		return nil
+		if getExport {
+			return printExportedValues(globals.PolarisReportingName, globals.PolarisReportingName, helmRelease.Config)
+		}
+		return printInstance(cmd, globals.PolarisReportingName, helmRelease)
 	},
 }
 
 // getBDBACmd display the BDBA instance
 var getBDBACmd = &cobra.Command{
 	Use:           "bdba -n NAMESPACE",
-	Example:       "synopsysctl get bdba -n <namespace>",
+	Example:       "synopsysctl get bdba -n <namespace>\nsynopsysctl get bdba --all-namespaces",
 	Short:         "Display the BDBA instance",
 	SilenceUsage:  true,
 	SilenceErrors: true,
@@ -270,49 +481,63 @@ var getBDBACmd = &cobra.Command{
 		return nil
 	},
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if isListingInstances() {
+			return printInstances(cmd, []string{globals.BDBAName})
+		}
+		if len(namespace) == 0 {
+			return fmt.Errorf("a namespace is required, use --namespace or --all-namespaces")
+		}
 		helmRelease, err := util.GetWithHelm3(globals.BDBAName, namespace, kubeConfigPath)
 		if err != nil {
 			return fmt.Errorf("failed to get BDBA values: %+v", err)
 		}
-		helmSetValues := helmRelease.Config
-		PrintComponent(helmSetValues, "YAML")
-		return nil
+		if getExport {
+			return printExportedValues(globals.BDBAName, globals.BDBAName, helmRelease.Config)
+		}
+		return printInstance(cmd, globals.BDBAName, helmRelease)
 	},
 }
 
 func init() {
 	//(PassCmd) getCmd.DisableFlagParsing = true // lets getCmd pass flags to kube/oc
 	rootCmd.AddCommand(getCmd)
+	getCmd.PersistentFlags().BoolVarP(&getAllNamespaces, "all-namespaces", "A", getAllNamespaces, "List the instances in all namespaces")
+	getCmd.PersistentFlags().StringVarP(&getOutputFormat, "output", "o", getOutputFormat, fmt.Sprintf("Output format [%s], the values of an instance are printed as yaml by default", util.OutputFormatDescription))
+	getCmd.PersistentFlags().StringVarP(&getSelector, "selector", "l", getSelector, "Selector to filter the listed instances on, supports '=', '==', '!=', 'in' and 'notin' with the keys app, name, version and state (e.g. -l state=Stopped)")
+
+	// All
+	getAllCmd.Flags().StringVarP(&namespace, "namespace", "n", namespace, "Namespace of the instance(s)")
+	getCmd.AddCommand(getAllCmd)
 
 	// Alert
 	getAlertCmd.Flags().StringVarP(&namespace, "namespace", "n", namespace, "Namespace of the instance(s)")
-	cobra.MarkFlagRequired(getAlertCmd.Flags(), "namespace")
+	getAlertCmd.Flags().BoolVar(&getExport, "export", getExport, "Print the values of the instance without its secrets in a form that 'synopsysctl create alert -f' accepts")
 	getCmd.AddCommand(getAlertCmd)
 
 	// Black Duck
 	getBlackDuckCmd.PersistentFlags().StringVarP(&namespace, "namespace", "n", namespace, "Namespace of the instance(s)")
-	cobra.MarkFlagRequired(getBlackDuckCmd.PersistentFlags(), "namespace")
+	getBlackDuckCmd.Flags().BoolVar(&getExport, "export", getExport, "Print the values of the instance without its secrets in a form that 'synopsysctl create blackduck -f' accepts")
 	getCmd.AddCommand(getBlackDuckCmd)
 
 	getBlackDuckCmd.AddCommand(getBlackDuckRootKeyCmd)
 
 	// OpsSight
 	getOpsSightCmd.Flags().StringVarP(&namespace, "namespace", "n", namespace, "Namespace of the instance(s)")
-	cobra.MarkFlagRequired(getOpsSightCmd.PersistentFlags(), "namespace")
+	getOpsSightCmd.Flags().BoolVar(&getExport, "export", getExport, "Print the values of the instance without its secrets in a form that 'synopsysctl create opssight -f' accepts")
 	getCmd.AddCommand(getOpsSightCmd)
 
 	// Polaris
 	getPolarisCmd.Flags().StringVarP(&namespace, "namespace", "n", namespace, "Namespace of the instance(s)")
-	cobra.MarkFlagRequired(getPolarisCmd.Flags(), "namespace")
+	getPolarisCmd.Flags().BoolVar(&getExport, "export", getExport, "Print the values of the instance without its secrets in a form that 'synopsysctl create polaris -f' accepts")
 	getCmd.AddCommand(getPolarisCmd)
 
 	// Polaris Reporting
 	getPolarisReportingCmd.Flags().StringVarP(&namespace, "namespace", "n", namespace, "Namespace of the instance(s)")
-	cobra.MarkFlagRequired(getPolarisReportingCmd.Flags(), "namespace")
+	getPolarisReportingCmd.Flags().BoolVar(&getExport, "export", getExport, "Print the values of the instance without its secrets in a form that 'synopsysctl create polaris-reporting -f' accepts")
 	getCmd.AddCommand(getPolarisReportingCmd)
 
 	// BDBA
 	getBDBACmd.Flags().StringVarP(&namespace, "namespace", "n", namespace, "Namespace of the instance(s)")
-	cobra.MarkFlagRequired(getBDBACmd.Flags(), "namespace")
+	getBDBACmd.Flags().BoolVar(&getExport, "export", getExport, "Print the values of the instance without its secrets in a form that 'synopsysctl create bdba -f' accepts")
 	getCmd.AddCommand(getBDBACmd)
 }
@@ -0,0 +1,118 @@
+/*
+Copyright (C) 2019 Synopsys, Inc.
+
+Licensed to the Apache Software Foundation (ASF) under one
+or more contributor license agreements. See the NOTICE file
+distributed with this work for additional information
+regarding copyright ownership. The ASF licenses this file
+to you under the Apache License, Version 2.0 (the
+"License"); you may not use this file except in compliance
+with the License. You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing,
+software distributed under the License is distributed on an
+"AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+KIND, either express or implied. See the License for the
+specific language governing permissions and limitations
+under the License.
+*/
+
+package synopsysctl
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/blackducksoftware/synopsysctl/pkg/util"
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+)
+
+// History Command Options and Defaults
+var historyRevision = 0
+
+// releaseRevision is a revision of the release of an instance
+type releaseRevision struct {
+	Revision      int      `json:"revision"`
+	Updated       string   `json:"updated"`
+	Status        string   `json:"status"`
+	Chart         string   `json:"chart"`
+	AppVersion    string   `json:"appVersion"`
+	ChangedValues []string `json:"changedValues"`
+	Description   string   `json:"description"`
+}
+
+// historyCmd lists the revisions of an instance
+var historyCmd = &cobra.Command{
+	Use:           "history PRODUCT [NAME] -n NAMESPACE",
+	Example:       "synopsysctl history blackduck <name> -n <namespace>\nsynopsysctl history blackduck <name> -n <namespace> --revision 2\nsynopsysctl history polaris -n <namespace>",
+	Short:         "List the revisions of an instance and the values that changed in each revision",
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	Args:          validateInstanceArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		product, name := getInstanceFromArgs(args)
+		releaseName, versionKey := getReleaseNameAndVersionKey(product, name)
+
+		revisions, err := util.HistoryWithHelm3(releaseName, namespace, kubeConfigPath)
+		if err != nil {
+			return fmt.Errorf("couldn't find the history of instance %s in namespace %s due to %+v", name, namespace, err)
+		}
+
+		if historyRevision > 0 {
+			for _, revision := range revisions {
+				if revision.Version == historyRevision {
+					values, redactedKeys := util.RedactHelmValues(util.GetReleaseValues(revision))
+					if cmd.Flags().Changed("output") {
+						return printOutput(cmd, values)
+					}
+					valuesBytes, err := yaml.Marshal(values)
+					if err != nil {
+						return fmt.Errorf("failed to convert the values of revision %d to YAML due to %+v", historyRevision, err)
+					}
+					fmt.Printf("# Values of revision %d of %s '%s' in namespace '%s'\n", historyRevision, product, name, namespace)
+					if len(redactedKeys) > 0 {
+						fmt.Printf("# The following secrets were removed: %s\n", strings.Join(redactedKeys, ", "))
+					}
+					fmt.Printf("%s", valuesBytes)
+					return nil
+				}
+			}
+			return fmt.Errorf("instance %s in namespace %s doesn't have revision %d", name, namespace, historyRevision)
+		}
+
+		releaseRevisions := []releaseRevision{}
+		table := util.Table{Columns: []util.TableColumn{{Header: "REVISION"}, {Header: "UPDATED"}, {Header: "STATUS"}, {Header: "CHART"}, {Header: "APP VERSION"}, {Header: "CHANGED VALUES"}, {Header: "DESCRIPTION", Wide: true}}}
+		for i, revision := range revisions {
+			changedValues := []string{}
+			if i > 0 {
+				changedValues = util.ChangedHelmValues(revisions[i-1].Config, revision.Config)
+			}
+			releaseRevision := releaseRevision{
+				Revision:      revision.Version,
+				Updated:       revision.Info.LastDeployed.Format("2006-01-02 15:04:05 MST"),
+				Status:        revision.Info.Status.String(),
+				Chart:         fmt.Sprintf("%s-%s", revision.Chart.Metadata.Name, revision.Chart.Metadata.Version),
+				AppVersion:    getReleaseAppVersion(revision, versionKey),
+				ChangedValues: changedValues,
+				Description:   revision.Info.Description,
+			}
+			releaseRevisions = append(releaseRevisions, releaseRevision)
+			table.Rows = append(table.Rows, []string{strconv.Itoa(releaseRevision.Revision), releaseRevision.Updated, releaseRevision.Status, releaseRevision.Chart,
+				releaseRevision.AppVersion, strings.Join(releaseRevision.ChangedValues, ", "), releaseRevision.Description})
+		}
+		return printOutput(cmd, releaseRevisions, table)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(historyCmd)
+
+	historyCmd.Flags().StringVarP(&namespace, "namespace", "n", namespace, "Namespace of the instance")
+	cobra.MarkFlagRequired(historyCmd.Flags(), "namespace")
+	historyCmd.Flags().IntVar(&historyRevision, "revision", historyRevision, "Show the values of this revision")
+	addOutputFlag(historyCmd, util.OutputTable)
+}
@@ -0,0 +1,178 @@
+/*
+Copyright (C) 2020 Synopsys, Inc.
+
+Licensed to the Apache Software Foundation (ASF) under one
+or more contributor license agreements. See the NOTICE file
+distributed with this work for additional information
+regarding copyright ownership. The ASF licenses this file
+to you under the Apache License, Version 2.0 (the
+"License"); you may not use this file except in compliance
+with the License. You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing,
+software distributed under the License is distributed on an
+"AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+KIND, either express or implied. See the License for the
+specific language governing permissions and limitations
+under the License.
+*/
+
+package synopsysctl
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/blackducksoftware/synopsysctl/pkg/util"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// Images Command Options and Defaults
+var imagesVersion = ""
+var imagesBundle = ""
+var imagesRelocateTarget = ""
+var imagesRelocateSourceLayout = ""
+var imagesRelocatePlainHTTP = false
+var imagesRelocateInsecureSkipTLSVerify = false
+
+// getProductImages returns the images used by a product from the bundle if it is set or by rendering its chart
+func getProductImages(product string) ([]string, error) {
+	if len(imagesBundle) > 0 {
+		bundleManifest, err := util.ReadBundleManifest(imagesBundle)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load the bundle: %+v", err)
+		}
+		if bundleManifest.Product != product {
+			return nil, fmt.Errorf("the bundle '%s' contains the resources for '%s', not '%s'", imagesBundle, bundleManifest.Product, product)
+		}
+		return bundleManifest.Images, nil
+	}
+
+	chartURL, err := getChartURL(product, imagesVersion)
+	if err != nil {
+		return nil, err
+	}
+	images, err := util.GetAllImagesFromChart(product, "default", chartURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the images for '%s': %+v", product, err)
+	}
+	return images, nil
+}
+
+// addImagesSourceFlags adds the flags that choose where the images of a product are found
+func addImagesSourceFlags(flags *pflag.FlagSet) {
+	flags.StringVar(&imagesVersion, "version", imagesVersion, "Version of the application (defaults to the latest)")
+	flags.StringVar(&imagesBundle, "bundle", imagesBundle, "Path to a bundle from 'synopsysctl bundle create' to get the images from")
+}
+
+// imagesCmd manages the container images of the Synopsys products
+var imagesCmd = &cobra.Command{
+	Use:   "images",
+	Short: "Manage the container images used by Synopsys resources",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return fmt.Errorf("must specify a sub-command")
+	},
+}
+
+// imagesListCmd lists the images a product will pull
+var imagesListCmd = &cobra.Command{
+	Use:           "list PRODUCT",
+	Example:       "synopsysctl images list blackduck --version 2020.4.0\nsynopsysctl images list alert --bundle alert-5.3.0-bundle.tgz\nsynopsysctl images list blackduck -o wide",
+	Short:         "List the images used by a product",
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			cmd.Help()
+			return fmt.Errorf("this command takes 1 argument, but got %+v", args)
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		images, err := getProductImages(args[0])
+		if err != nil {
+			return err
+		}
+		// Without --output, only the images are printed so that they can be piped to other commands
+		if format, _ := cmd.Flags().GetString("output"); len(format) == 0 {
+			for _, image := range images {
+				fmt.Printf("%s\n", image)
+			}
+			return nil
+		}
+		table := util.Table{Columns: []util.TableColumn{{Header: "IMAGE"}, {Header: "REGISTRY", Wide: true}, {Header: "NAME", Wide: true}, {Header: "TAG", Wide: true}}}
+		for _, image := range images {
+			table.Rows = append(table.Rows, []string{image, util.ParseImageRepo(image), util.ParseImageName(image), util.ParseImageTag(image)})
+		}
+		return printOutput(cmd, images, table)
+	},
+}
+
+// imagesRelocateCmd copies the images a product will pull into a private registry or an OCI image layout
+var imagesRelocateCmd = &cobra.Command{
+	Use:           "relocate PRODUCT --to REGISTRY|oci:DIRECTORY",
+	Example:       "synopsysctl images relocate blackduck --version 2020.4.0 --to registry.local:5000/blackducksoftware\nsynopsysctl images relocate alert --version 5.3.0 --to oci:/tmp/alert-images\nsynopsysctl images relocate alert --version 5.3.0 --from-oci-layout /tmp/alert-images --to registry.local:5000/blackducksoftware",
+	Short:         "Copy the images used by a product into a private registry or an OCI image layout",
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			cmd.Help()
+			return fmt.Errorf("this command takes 1 argument, but got %+v", args)
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		images, err := getProductImages(args[0])
+		if err != nil {
+			return err
+		}
+		relocator, err := util.NewImageRelocator(imagesRelocateSourceLayout, imagesRelocatePlainHTTP, imagesRelocateInsecureSkipTLSVerify)
+		if err != nil {
+			return err
+		}
+
+		relocatedImages := []string{}
+		for _, image := range images {
+			log.Infof("copying '%s'", image)
+			relocatedImage, err := relocator.Relocate(context.Background(), image, imagesRelocateTarget)
+			if err != nil {
+				return fmt.Errorf("failed to relocate the images for '%s': %+v", args[0], err)
+			}
+			relocatedImages = append(relocatedImages, relocatedImage)
+		}
+
+		if strings.HasPrefix(imagesRelocateTarget, util.OCILayoutPrefix) {
+			log.Infof("%d images have been successfully copied to the OCI image layout '%s'", len(relocatedImages), strings.TrimPrefix(imagesRelocateTarget, util.OCILayoutPrefix))
+			return nil
+		}
+		log.Infof("%d images have been successfully copied to '%s', use them with the flags:", len(relocatedImages), imagesRelocateTarget)
+		if args[0] == util.BlackDuckName {
+			fmt.Printf("--registry=%s --image-registries=%s\n", imagesRelocateTarget, strings.Join(relocatedImages, ","))
+		} else {
+			fmt.Printf("--registry=%s\n", imagesRelocateTarget)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(imagesCmd)
+
+	addImagesSourceFlags(imagesListCmd.Flags())
+	addOutputFlag(imagesListCmd, "")
+	imagesCmd.AddCommand(imagesListCmd)
+
+	addImagesSourceFlags(imagesRelocateCmd.Flags())
+	imagesRelocateCmd.Flags().StringVar(&imagesRelocateTarget, "to", imagesRelocateTarget, "Registry to copy the images to e.g. registry.local:5000/blackducksoftware, or an OCI image layout directory prefixed with 'oci:'")
+	cobra.MarkFlagRequired(imagesRelocateCmd.Flags(), "to")
+	imagesRelocateCmd.Flags().StringVar(&imagesRelocateSourceLayout, "from-oci-layout", imagesRelocateSourceLayout, "OCI image layout directory to copy the images from instead of their registries")
+	imagesRelocateCmd.Flags().BoolVar(&imagesRelocatePlainHTTP, "plain-http", imagesRelocatePlainHTTP, "Use HTTP instead of HTTPS to access the target registry")
+	imagesRelocateCmd.Flags().BoolVar(&imagesRelocateInsecureSkipTLSVerify, "registry-insecure-skip-tls-verify", imagesRelocateInsecureSkipTLSVerify, "Target registry's certificate won't be validated")
+	imagesCmd.AddCommand(imagesRelocateCmd)
+}
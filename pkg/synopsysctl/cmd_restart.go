@@ -0,0 +1,118 @@
+/*
+Copyright (C) 2019 Synopsys, Inc.
+
+Licensed to the Apache Software Foundation (ASF) under one
+or more contributor license agreements. See the NOTICE file
+distributed with this work for additional information
+regarding copyright ownership. The ASF licenses this file
+to you under the Apache License, Version 2.0 (the
+"License"); you may not use this file except in compliance
+with the License. You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing,
+software distributed under the License is distributed on an
+"AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+KIND, either express or implied. See the License for the
+specific language governing permissions and limitations
+under the License.
+*/
+
+package synopsysctl
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/blackducksoftware/synopsysctl/pkg/util"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	appsv1 "k8s.io/api/apps/v1"
+)
+
+// Restart Command Options and Defaults
+var restartComponents = []string{}
+var restartTimeout = util.DefaultWaitTimeout
+
+// getDeploymentComponent returns the component label of a deployment, or its name if it doesn't have one
+func getDeploymentComponent(deployment appsv1.Deployment) string {
+	if component, ok := deployment.Labels["component"]; ok && len(component) > 0 {
+		return component
+	}
+	return deployment.Name
+}
+
+// isDeploymentInComponents returns true if the deployment's component label or name matches one of the components
+func isDeploymentInComponents(deployment appsv1.Deployment, components []string) bool {
+	for _, component := range components {
+		if getDeploymentComponent(deployment) == component || deployment.Name == component || strings.HasSuffix(deployment.Name, fmt.Sprintf("-%s", component)) {
+			return true
+		}
+	}
+	return false
+}
+
+// restartCmd restarts the deployments of an instance one at a time
+var restartCmd = &cobra.Command{
+	Use:           "restart PRODUCT [NAME] -n NAMESPACE",
+	Example:       "synopsysctl restart blackduck <name> -n <namespace>\nsynopsysctl restart blackduck <name> -n <namespace> --component webapp,jobrunner\nsynopsysctl restart polaris -n <namespace>",
+	Short:         "Restart the pods of an instance with a rolling restart of its deployments",
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	Args:          validateInstanceArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		product, name := getInstanceFromArgs(args)
+		releaseName, _ := getReleaseNameAndVersionKey(product, name)
+
+		instance, err := util.GetWithHelm3(releaseName, namespace, kubeConfigPath)
+		if err != nil {
+			return fmt.Errorf("couldn't find instance %s in namespace %s", name, namespace)
+		}
+
+		deployments := []appsv1.Deployment{}
+		availableComponents := []string{}
+		for _, deploymentName := range util.GetReleaseWorkloads(instance.Manifest).Deployments {
+			deployment, err := util.GetDeployment(kubeClient, namespace, deploymentName)
+			if err != nil {
+				return fmt.Errorf("unable to get deployment '%s' in namespace '%s' due to %+v", deploymentName, namespace, err)
+			}
+			availableComponents = append(availableComponents, getDeploymentComponent(*deployment))
+			if len(restartComponents) == 0 || isDeploymentInComponents(*deployment, restartComponents) {
+				deployments = append(deployments, *deployment)
+			}
+		}
+		if len(deployments) == 0 {
+			if len(restartComponents) > 0 {
+				return fmt.Errorf("%s '%s' doesn't have the components %s, the components are %s", product, name, strings.Join(restartComponents, ", "), strings.Join(availableComponents, ", "))
+			}
+			return fmt.Errorf("%s '%s' doesn't have any deployments to restart", product, name)
+		}
+
+		// The deployments are restarted one at a time so that the rest of the instance stays available
+		for _, deployment := range deployments {
+			start := time.Now()
+			log.Infof("restarting deployment '%s'...", deployment.Name)
+			if _, err := util.RestartDeployment(kubeClient, deployment); err != nil {
+				return fmt.Errorf("failed to restart deployment '%s' due to %+v", deployment.Name, err)
+			}
+			if err := util.WaitForDeploymentRollout(kubeClient, namespace, deployment.Name, restartTimeout); err != nil {
+				return fmt.Errorf("deployment '%s' isn't ready after the restart: %s", deployment.Name, err)
+			}
+			log.Infof("successfully restarted deployment '%s' in %s", deployment.Name, time.Since(start).Round(time.Second))
+		}
+
+		log.Infof("successfully restarted %d deployment(s) of %s '%s' in namespace '%s'", len(deployments), product, name, namespace)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(restartCmd)
+
+	restartCmd.Flags().StringVarP(&namespace, "namespace", "n", namespace, "Namespace of the instance")
+	cobra.MarkFlagRequired(restartCmd.Flags(), "namespace")
+	restartCmd.Flags().StringSliceVar(&restartComponents, "component", restartComponents, "Only restart the deployments of these components (e.g. webapp,jobrunner), all deployments are restarted by default")
+	restartCmd.Flags().DurationVar(&restartTimeout, "timeout", restartTimeout, "How long to wait for each deployment to be ready after it is restarted")
+}
@@ -0,0 +1,230 @@
+/*
+Copyright (C) 2019 Synopsys, Inc.
+
+Licensed to the Apache Software Foundation (ASF) under one
+or more contributor license agreements. See the NOTICE file
+distributed with this work for additional information
+regarding copyright ownership. The ASF licenses this file
+to you under the Apache License, Version 2.0 (the
+"License"); you may not use this file except in compliance
+with the License. You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing,
+software distributed under the License is distributed on an
+"AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+KIND, either express or implied. See the License for the
+specific language governing permissions and limitations
+under the License.
+*/
+
+package synopsysctl
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	blackduckutil "github.com/blackducksoftware/synopsysctl/pkg/blackduck/util"
+	"github.com/blackducksoftware/synopsysctl/pkg/util"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// Restore Command Options and Defaults
+var restoreFrom = ""
+var restoreTimeout = 30 * time.Minute
+
+// parseBackupLocation returns the PVC and the directory of a backup, the PVC is empty if the backup is in a local directory
+func parseBackupLocation(location string) (string, string, error) {
+	if !strings.HasPrefix(location, backupPVCPrefix) {
+		return "", location, nil
+	}
+	parts := strings.SplitN(strings.TrimPrefix(location, backupPVCPrefix), "/", 2)
+	if len(parts) != 2 || len(parts[0]) == 0 || len(parts[1]) == 0 {
+		return "", "", fmt.Errorf("the backup '%s' must be in the format %s<claim name>/<backup directory>", location, backupPVCPrefix)
+	}
+	return parts[0], parts[1], nil
+}
+
+// parseBackupKeys returns the seal key and the master key from the base64 encoded first two lines of the output of BackupKeysCommand
+func parseBackupKeys(output string) (string, string, error) {
+	lines := strings.SplitN(output, "\n", 3)
+	if len(lines) < 2 {
+		return "", "", fmt.Errorf("the keys are missing from the output of the backup keys job")
+	}
+	sealKey, err := base64.StdEncoding.DecodeString(strings.TrimSpace(lines[0]))
+	if err != nil {
+		return "", "", fmt.Errorf("unable to decode the seal key due to %+v", err)
+	}
+	masterKey, err := base64.StdEncoding.DecodeString(strings.TrimSpace(lines[1]))
+	if err != nil {
+		return "", "", fmt.Errorf("unable to decode the master key due to %+v", err)
+	}
+	return string(sealKey), string(masterKey), nil
+}
+
+// startBlackDuckPostgres starts the internal database of a stopped Black Duck instance on its own
+func startBlackDuckPostgres(namespace string, name string, timeout time.Duration) error {
+	postgresDeployment, err := util.GetDeployment(kubeClient, namespace, util.GetResourceName(name, util.BlackDuckName, "postgres"))
+	if err != nil {
+		return fmt.Errorf("unable to get the postgres deployment of Black Duck '%s' due to %+v", name, err)
+	}
+	replicas := int32(1)
+	if _, err := util.PatchDeploymentForReplicas(kubeClient, postgresDeployment, &replicas); err != nil {
+		return fmt.Errorf("unable to start the postgres deployment of Black Duck '%s' due to %+v", name, err)
+	}
+	if err := util.WaitForDeploymentRollout(kubeClient, namespace, postgresDeployment.Name, timeout); err != nil {
+		return fmt.Errorf("the postgres deployment of Black Duck '%s' isn't ready: %s", name, err)
+	}
+	return nil
+}
+
+// restoreBlackDuck stops a Black Duck instance, restores the database, the seal key and the master key from a backup taken
+// with the backup command, and starts the instance again
+func restoreBlackDuck(cmd *cobra.Command, namespace string, name string, from string, timeout time.Duration) error {
+	claimName, backupDir, err := parseBackupLocation(from)
+	if err != nil {
+		return err
+	}
+	instance, err := util.GetWithHelm3(name, namespace, kubeConfigPath)
+	if err != nil {
+		return fmt.Errorf("couldn't find instance %s in namespace %s", name, namespace)
+	}
+	postgres, err := getBlackDuckPostgresConnection(instance, namespace, name)
+	if err != nil {
+		return err
+	}
+
+	// the backup is checked and its keys are read before the instance is stopped
+	var dumpFile *os.File
+	var sealKey, masterKey string
+	if len(claimName) == 0 {
+		keys := map[string]*string{blackduckutil.BackupSealKeyFile: &sealKey, blackduckutil.BackupMasterKeyFile: &masterKey}
+		for fileName, key := range keys {
+			value, err := ioutil.ReadFile(filepath.Join(backupDir, fileName))
+			if err != nil {
+				return fmt.Errorf("error reading the backup file '%s' due to %+v", filepath.Join(backupDir, fileName), err)
+			}
+			*key = string(value)
+		}
+		if complete, err := blackduckutil.IsCompleteDump(filepath.Join(backupDir, blackduckutil.BackupDatabaseFile)); err == nil && !complete {
+			return fmt.Errorf("the backup file '%s' is incomplete, it doesn't end with '%s'", filepath.Join(backupDir, blackduckutil.BackupDatabaseFile), blackduckutil.DumpCompleteMarker)
+		}
+		dumpFile, err = os.Open(filepath.Join(backupDir, blackduckutil.BackupDatabaseFile))
+		if err != nil {
+			return fmt.Errorf("error reading the backup file '%s' due to %+v", filepath.Join(backupDir, blackduckutil.BackupDatabaseFile), err)
+		}
+		defer dumpFile.Close()
+	} else {
+		if _, err := util.GetPVC(kubeClient, namespace, claimName); err != nil {
+			return fmt.Errorf("unable to find PVC '%s' in namespace '%s' due to %+v", claimName, namespace, err)
+		}
+		// the keys are copied over exec instead of the job's logs, so that they don't end up in the logs of the cluster
+		var keys bytes.Buffer
+		if err := runJobCommand(blackduckutil.NewBackupKeysJob(namespace, name, claimName), blackduckutil.BackupKeysCommand(backupDir), &keys, timeout); err != nil {
+			return fmt.Errorf("unable to read the keys of the backup '%s' due to %+v", from, err)
+		}
+		if sealKey, masterKey, err = parseBackupKeys(keys.String()); err != nil {
+			return err
+		}
+	}
+
+	log.Infof("stopping Black Duck '%s' in namespace '%s'...", name, namespace)
+	if err := setBlackDuckStatus(cmd.Flags(), instance, "Stopped"); err != nil {
+		return err
+	}
+	stoppedErr := func(err error) error {
+		return fmt.Errorf("%+v. Black Duck '%s' is stopped, run 'synopsysctl start blackduck %s -n %s' to start it again", err, name, name, namespace)
+	}
+	if err := util.WaitForPodsStopped(kubeClient, namespace, getInstanceLabelSelector(util.BlackDuckName, name), timeout); err != nil {
+		return stoppedErr(fmt.Errorf("Black Duck '%s' didn't stop: %s", name, err))
+	}
+
+	// the internal database is started on its own to restore the dump into it
+	if !isBlackDuckPostgresExternal(instance) {
+		if err := startBlackDuckPostgres(namespace, name, timeout); err != nil {
+			return stoppedErr(err)
+		}
+	}
+
+	log.Infof("restoring the database of Black Duck '%s' in namespace '%s' from '%s'...", name, namespace, from)
+	job := blackduckutil.NewRestoreJob(namespace, name, postgres, claimName, backupDir)
+	if len(claimName) == 0 {
+		err = runJob(job, dumpFile, timeout)
+	} else {
+		err = runJob(job, nil, timeout)
+	}
+	if err != nil {
+		return stoppedErr(fmt.Errorf("unable to restore the database due to %+v", err))
+	}
+
+	log.Infof("starting Black Duck '%s' in namespace '%s' with the seal key of the backup...", name, namespace)
+	if len(sealKey) > 0 {
+		util.SetHelmValueInMap(instance.Config, []string{"sealKey"}, sealKey)
+	}
+	if err := setBlackDuckStatus(cmd.Flags(), instance, "Running"); err != nil {
+		return stoppedErr(err)
+	}
+
+	// the instance has to be ready to verify the restore and to put back the master key
+	log.Infof("waiting up to %s for Black Duck '%s' in namespace '%s' to be ready...", timeout, name, namespace)
+	if err := util.WaitForInstanceReady(kubeClient, namespace, getInstanceLabelSelector(util.BlackDuckName, name), timeout); err != nil {
+		return fmt.Errorf("Black Duck '%s' isn't ready after the restore: %s", name, err)
+	}
+	if _, err := putBlackDuckMasterKey(namespace, name, sealKey, masterKey); err != nil {
+		return fmt.Errorf("unable to restore the master key of Black Duck '%s' due to %+v", name, err)
+	}
+	return nil
+}
+
+// restoreCmd restores a Synopsys resource from a backup
+var restoreCmd = &cobra.Command{
+	Use:   "restore",
+	Short: "Restore a Synopsys resource from a backup",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return fmt.Errorf("must specify a sub-command")
+	},
+}
+
+// restoreBlackDuckCmd restores the database, the seal key and the master key of a Black Duck instance from a backup
+var restoreBlackDuckCmd = &cobra.Command{
+	Use:           "blackduck NAME -n NAMESPACE --from DIRECTORY|pvc:CLAIM_NAME/DIRECTORY",
+	Example:       "synopsysctl restore blackduck <name> -n <namespace> --from <backup directory>\nsynopsysctl restore blackduck <name> -n <namespace> --from pvc:<claim name>/<backup directory>",
+	Short:         "Restore the database, the seal key and the master key of a Black Duck instance from a backup",
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			cmd.Help()
+			return fmt.Errorf("this command takes 1 argument, but got %+v", args)
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		start := time.Now()
+		if err := restoreBlackDuck(cmd, namespace, args[0], restoreFrom, restoreTimeout); err != nil {
+			return fmt.Errorf("failed to restore Black Duck '%s' in namespace '%s' due to %+v", args[0], namespace, err)
+		}
+		log.Infof("successfully restored Black Duck '%s' in namespace '%s' from '%s' in %s", args[0], namespace, restoreFrom, time.Since(start).Round(time.Second))
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(restoreCmd)
+
+	restoreBlackDuckCmd.Flags().StringVarP(&namespace, "namespace", "n", namespace, "Namespace of the instance")
+	cobra.MarkFlagRequired(restoreBlackDuckCmd.Flags(), "namespace")
+	restoreBlackDuckCmd.Flags().StringVar(&restoreFrom, "from", restoreFrom, "Backup directory to restore, in a local directory or in a PVC in the instance's namespace (pvc:<claim name>/<backup directory>)")
+	cobra.MarkFlagRequired(restoreBlackDuckCmd.Flags(), "from")
+	restoreBlackDuckCmd.Flags().DurationVar(&restoreTimeout, "timeout", restoreTimeout, "How long to wait for each step of the restore to complete")
+	addChartLocationPathFlag(restoreBlackDuckCmd)
+	restoreCmd.AddCommand(restoreBlackDuckCmd)
+}
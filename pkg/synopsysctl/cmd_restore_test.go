@@ -0,0 +1,69 @@
+/*
+Copyright (C) 2020 Synopsys, Inc.
+
+Licensed to the Apache Software Foundation (ASF) under one
+or more contributor license agreements. See the NOTICE file
+distributed with this work for additional information
+regarding copyright ownership. The ASF licenses this file
+to you under the Apache License, Version 2.0 (the
+"License"); you may not use this file except in compliance
+with the License. You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing,
+software distributed under the License is distributed on an
+"AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+KIND, either express or implied. See the License for the
+specific language governing permissions and limitations
+under the License.
+*/
+
+package synopsysctl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseBackupLocation(t *testing.T) {
+	var tests = []struct {
+		location          string
+		expectedClaimName string
+		expectedBackupDir string
+		valid             bool
+	}{
+		{location: "/backups/ns-bd1-20200401200000", expectedBackupDir: "/backups/ns-bd1-20200401200000", valid: true},
+		{location: "backups/ns-bd1-20200401200000", expectedBackupDir: "backups/ns-bd1-20200401200000", valid: true},
+		{location: "pvc:backups/ns-bd1-20200401200000", expectedClaimName: "backups", expectedBackupDir: "ns-bd1-20200401200000", valid: true},
+		{location: "pvc:backups/2020/ns-bd1-20200401200000", expectedClaimName: "backups", expectedBackupDir: "2020/ns-bd1-20200401200000", valid: true},
+		{location: "pvc:backups", valid: false},
+		{location: "pvc:backups/", valid: false},
+		{location: "pvc:/ns-bd1-20200401200000", valid: false},
+		{location: "pvc:", valid: false},
+	}
+
+	for _, test := range tests {
+		claimName, backupDir, err := parseBackupLocation(test.location)
+		if !test.valid {
+			assert.Error(t, err, test.location)
+			continue
+		}
+		assert.NoError(t, err, test.location)
+		assert.Equal(t, test.expectedClaimName, claimName, test.location)
+		assert.Equal(t, test.expectedBackupDir, backupDir, test.location)
+	}
+}
+
+func TestParseBackupKeys(t *testing.T) {
+	sealKey, masterKey, err := parseBackupKeys("c2VhbA==\nbWsKeA==\n")
+	assert.NoError(t, err)
+	assert.Equal(t, "seal", sealKey)
+	assert.Equal(t, "mk\nx", masterKey)
+
+	_, _, err = parseBackupKeys("c2VhbA==")
+	assert.Error(t, err)
+	_, _, err = parseBackupKeys("not base64\nbWsKeA==\n")
+	assert.Error(t, err)
+}
@@ -0,0 +1,295 @@
+/*
+Copyright (C) 2019 Synopsys, Inc.
+
+Licensed to the Apache Software Foundation (ASF) under one
+or more contributor license agreements. See the NOTICE file
+distributed with this work for additional information
+regarding copyright ownership. The ASF licenses this file
+to you under the Apache License, Version 2.0 (the
+"License"); you may not use this file except in compliance
+with the License. You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing,
+software distributed under the License is distributed on an
+"AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+KIND, either express or implied. See the License for the
+specific language governing permissions and limitations
+under the License.
+*/
+
+package synopsysctl
+
+import (
+	"fmt"
+
+	"github.com/blackducksoftware/synopsysctl/pkg/alert"
+	"github.com/blackducksoftware/synopsysctl/pkg/blackduck"
+	"github.com/blackducksoftware/synopsysctl/pkg/globals"
+	"github.com/blackducksoftware/synopsysctl/pkg/util"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"helm.sh/helm/v3/pkg/release"
+)
+
+// Rollback Command Options and Defaults
+var rollbackRevision = 0
+
+// alertSecretNameKeys are the Helm values with the names of the secrets that synopsysctl manages for Alert
+var alertSecretNameKeys = []string{"webserverCustomCertificatesSecretName", "javaKeystoreSecretName"}
+
+// blackDuckSecretNameKeys are the Helm values with the names of the secrets that synopsysctl manages for Black Duck
+var blackDuckSecretNameKeys = []string{"tlsCertSecretName", "proxyCertSecretName", "certAuthCACertSecretName"}
+
+// rollbackRelease rolls back a release to rollbackRevision and returns the release at that revision. The secrets
+// in secretNameKeys are restored before the rollback so that the pods start with them
+func rollbackRelease(releaseName string, secretNameKeys []string) (*release.Release, error) {
+	currentRelease, err := util.GetWithHelm3(releaseName, namespace, kubeConfigPath)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't find instance %s in namespace %s", releaseName, namespace)
+	}
+	revision := rollbackRevision
+	if revision == 0 {
+		revision = currentRelease.Version - 1
+	}
+	if revision < 1 {
+		return nil, fmt.Errorf("release '%s' doesn't have a previous revision to roll back to", releaseName)
+	}
+	if revision == currentRelease.Version {
+		return nil, fmt.Errorf("release '%s' is already at revision %d", releaseName, revision)
+	}
+	targetRelease, err := util.GetRevisionWithHelm3(releaseName, namespace, revision, kubeConfigPath)
+	if err != nil {
+		return nil, err
+	}
+
+	// Restore the secrets that synopsysctl manages outside of the chart
+	for _, key := range secretNameKeys {
+		secretName, ok := targetRelease.Config[key].(string)
+		if !ok || len(secretName) == 0 {
+			continue
+		}
+		// Save the current secret so that the rollback can be rolled back
+		if err := util.SaveSecretRevision(kubeClient, namespace, releaseName, secretName, currentRelease.Version); err != nil {
+			return nil, err
+		}
+		restored, err := util.RestoreSecretRevision(kubeClient, namespace, releaseName, secretName, revision)
+		if err != nil {
+			return nil, err
+		}
+		if restored {
+			log.Infof("restored secret '%s' to revision %d", secretName, revision)
+		}
+	}
+
+	if err := util.RollbackWithHelm3(releaseName, namespace, revision, kubeConfigPath); err != nil {
+		return nil, err
+	}
+	return targetRelease, nil
+}
+
+// rollbackCmd rolls back a Synopsys resource in the cluster
+var rollbackCmd = &cobra.Command{
+	Use:   "rollback",
+	Short: "Roll back a Synopsys resource to a previous revision",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return fmt.Errorf("must specify a sub-command")
+	},
+}
+
+// rollbackAlertCmd rolls back an Alert instance
+var rollbackAlertCmd = &cobra.Command{
+	Use:           "alert NAME -n NAMESPACE",
+	Example:       "synopsysctl rollback alert <name> -n <namespace>\nsynopsysctl rollback alert <name> -n <namespace> --revision 2",
+	Short:         "Roll back an Alert instance",
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			cmd.Help()
+			return fmt.Errorf("this command takes 1 argument, but got %+v", args)
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		alertName := args[0]
+		helmReleaseName := fmt.Sprintf("%s%s", alertName, globals.AlertPostSuffix)
+
+		targetRelease, err := rollbackRelease(helmReleaseName, alertSecretNameKeys)
+		if err != nil {
+			cleanErrorMsg := cleanAlertHelmError(err.Error(), helmReleaseName, alertName)
+			return fmt.Errorf("failed to roll back Alert: %+v", cleanErrorMsg)
+		}
+
+		// Restore the exposed service or route of the revision
+		values := util.MergeMaps(targetRelease.Chart.Values, targetRelease.Config)
+		if err := alert.CRUDServiceOrRoute(restconfig, kubeClient, namespace, alertName, values["exposeui"], values["exposedServiceType"], true); err != nil {
+			return fmt.Errorf("failed to restore the exposed service due to %+v", err)
+		}
+
+		log.Infof("Alert has been successfully rolled back to revision %d in namespace '%s'!", targetRelease.Version, namespace)
+		return nil
+	},
+}
+
+// rollbackBlackDuckCmd rolls back a Black Duck instance
+var rollbackBlackDuckCmd = &cobra.Command{
+	Use:           "blackduck NAME -n NAMESPACE",
+	Example:       "synopsysctl rollback blackduck <name> -n <namespace>\nsynopsysctl rollback blackduck <name> -n <namespace> --revision 2",
+	Short:         "Roll back a Black Duck instance",
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			cmd.Help()
+			return fmt.Errorf("this command takes 1 argument, but got %+v", args)
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		targetRelease, err := rollbackRelease(args[0], blackDuckSecretNameKeys)
+		if err != nil {
+			return fmt.Errorf("failed to roll back Black Duck due to %+v", err)
+		}
+
+		// Restore the exposed service or route of the revision
+		values := util.MergeMaps(targetRelease.Chart.Values, targetRelease.Config)
+		if err := blackduck.CRUDServiceOrRoute(restconfig, kubeClient, namespace, args[0], values["exposeui"], values["exposedServiceType"], true); err != nil {
+			return err
+		}
+
+		log.Infof("Black Duck has been successfully rolled back to revision %d in namespace '%s'!", targetRelease.Version, namespace)
+		return nil
+	},
+}
+
+// rollbackOpsSightCmd rolls back an OpsSight instance
+var rollbackOpsSightCmd = &cobra.Command{
+	Use:           "opssight NAME -n NAMESPACE",
+	Example:       "synopsysctl rollback opssight <name> -n <namespace>\nsynopsysctl rollback opssight <name> -n <namespace> --revision 2",
+	Short:         "Roll back an OpsSight instance",
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			cmd.Help()
+			return fmt.Errorf("this command takes 1 argument, but got %+v", args)
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		targetRelease, err := rollbackRelease(args[0], nil)
+		if err != nil {
+			return fmt.Errorf("failed to roll back OpsSight due to %+v", err)
+		}
+		log.Infof("OpsSight has been successfully rolled back to revision %d in namespace '%s'!", targetRelease.Version, namespace)
+		return nil
+	},
+}
+
+// rollbackPolarisCmd rolls back a Polaris instance
+var rollbackPolarisCmd = &cobra.Command{
+	Use:           "polaris -n NAMESPACE",
+	Example:       "synopsysctl rollback polaris -n <namespace>\nsynopsysctl rollback polaris -n <namespace> --revision 2",
+	Short:         "Roll back a Polaris instance",
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 0 {
+			cmd.Help()
+			return fmt.Errorf("this command takes 0 arguments, but got %+v", args)
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		targetRelease, err := rollbackRelease(globals.PolarisName, nil)
+		if err != nil {
+			return fmt.Errorf("failed to roll back Polaris due to %+v", err)
+		}
+		log.Infof("Polaris has been successfully rolled back to revision %d in namespace '%s'!", targetRelease.Version, namespace)
+		return nil
+	},
+}
+
+// rollbackPolarisReportingCmd rolls back a Polaris-Reporting instance
+var rollbackPolarisReportingCmd = &cobra.Command{
+	Use:           "polaris-reporting -n NAMESPACE",
+	Example:       "synopsysctl rollback polaris-reporting -n <namespace>\nsynopsysctl rollback polaris-reporting -n <namespace> --revision 2",
+	Short:         "Roll back a Polaris-Reporting instance",
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 0 {
+			cmd.Help()
+			return fmt.Errorf("this command takes 0 arguments, but got %+v", args)
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		targetRelease, err := rollbackRelease(globals.PolarisReportingName, nil)
+		if err != nil {
+			return fmt.Errorf("failed to roll back Polaris-Reporting due to %+v", err)
+		}
+		log.Infof("Polaris-Reporting has been successfully rolled back to revision %d in namespace '%s'!", targetRelease.Version, namespace)
+		return nil
+	},
+}
+
+// rollbackBDBACmd rolls back a BDBA instance
+var rollbackBDBACmd = &cobra.Command{
+	Use:           "bdba -n NAMESPACE",
+	Example:       "synopsysctl rollback bdba -n <namespace>\nsynopsysctl rollback bdba -n <namespace> --revision 2",
+	Short:         "Roll back a BDBA instance",
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 0 {
+			cmd.Help()
+			return fmt.Errorf("this command takes 0 arguments, but got %+v", args)
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		targetRelease, err := rollbackRelease(globals.BDBAName, nil)
+		if err != nil {
+			return fmt.Errorf("failed to roll back BDBA due to %+v", err)
+		}
+		log.Infof("BDBA has been successfully rolled back to revision %d in namespace '%s'!", targetRelease.Version, namespace)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(rollbackCmd)
+
+	rollbackAlertCmd.Flags().StringVarP(&namespace, "namespace", "n", namespace, "Namespace of the instance")
+	cobra.MarkFlagRequired(rollbackAlertCmd.Flags(), "namespace")
+	rollbackAlertCmd.Flags().IntVar(&rollbackRevision, "revision", rollbackRevision, "Revision to roll back to (the previous revision by default)")
+	rollbackCmd.AddCommand(rollbackAlertCmd)
+
+	rollbackBlackDuckCmd.Flags().StringVarP(&namespace, "namespace", "n", namespace, "Namespace of the instance")
+	cobra.MarkFlagRequired(rollbackBlackDuckCmd.Flags(), "namespace")
+	rollbackBlackDuckCmd.Flags().IntVar(&rollbackRevision, "revision", rollbackRevision, "Revision to roll back to (the previous revision by default)")
+	rollbackCmd.AddCommand(rollbackBlackDuckCmd)
+
+	rollbackOpsSightCmd.Flags().StringVarP(&namespace, "namespace", "n", namespace, "Namespace of the instance")
+	cobra.MarkFlagRequired(rollbackOpsSightCmd.Flags(), "namespace")
+	rollbackOpsSightCmd.Flags().IntVar(&rollbackRevision, "revision", rollbackRevision, "Revision to roll back to (the previous revision by default)")
+	rollbackCmd.AddCommand(rollbackOpsSightCmd)
+
+	rollbackPolarisCmd.Flags().StringVarP(&namespace, "namespace", "n", namespace, "Namespace of the instance")
+	cobra.MarkFlagRequired(rollbackPolarisCmd.Flags(), "namespace")
+	rollbackPolarisCmd.Flags().IntVar(&rollbackRevision, "revision", rollbackRevision, "Revision to roll back to (the previous revision by default)")
+	rollbackCmd.AddCommand(rollbackPolarisCmd)
+
+	rollbackPolarisReportingCmd.Flags().StringVarP(&namespace, "namespace", "n", namespace, "Namespace of the instance")
+	cobra.MarkFlagRequired(rollbackPolarisReportingCmd.Flags(), "namespace")
+	rollbackPolarisReportingCmd.Flags().IntVar(&rollbackRevision, "revision", rollbackRevision, "Revision to roll back to (the previous revision by default)")
+	rollbackCmd.AddCommand(rollbackPolarisReportingCmd)
+
+	rollbackBDBACmd.Flags().StringVarP(&namespace, "namespace", "n", namespace, "Namespace of the instance")
+	cobra.MarkFlagRequired(rollbackBDBACmd.Flags(), "namespace")
+	rollbackBDBACmd.Flags().IntVar(&rollbackRevision, "revision", rollbackRevision, "Revision to roll back to (the previous revision by default)")
+	rollbackCmd.AddCommand(rollbackBDBACmd)
+}
@@ -26,6 +26,7 @@ import (
 	"os"
 	"strings"
 
+	"github.com/blackducksoftware/synopsysctl/pkg/util"
 	homedir "github.com/mitchellh/go-homedir"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
@@ -40,6 +41,24 @@ var cfgFile string
 var kubeConfigPath = ""
 var insecureSkipTLSVerify = false
 var logLevelCtl = "info"
+var chartCacheDir = util.DefaultChartCacheDir()
+var chartRepositoryURLs = []string{}
+var chartRepositoryUsername = ""
+var chartRepositoryPassword = ""
+var chartRepositoryToken = ""
+var chartRepositoryCAFile = ""
+var chartRepositoryCertFile = ""
+var chartRepositoryKeyFile = ""
+var verifyChart = false
+var chartKeyring = util.DefaultChartKeyring()
+var chartIndexTimeout = util.DefaultChartIndexTimeout
+var chartIndexTTL = util.DefaultChartIndexTTL
+
+// offlineCommands are the commands that don't need access to a cluster
+var offlineCommands = []string{"synopsysctl chart", "synopsysctl bundle", "synopsysctl images", "synopsysctl versions"}
+
+// chartIndexCommands are the commands that need the chart repositories' index to find the applications' charts
+var chartIndexCommands = []string{"synopsysctl create", "synopsysctl update", "synopsysctl scale", "synopsysctl restore", "synopsysctl clone", "synopsysctl chart pull", "synopsysctl bundle create", "synopsysctl images", "synopsysctl versions"}
 
 // synopsysctlVersion is the current version of the synopsysctl utility
 var synopsysctlVersion string
@@ -57,8 +76,27 @@ var rootCmd = &cobra.Command{
 			return err
 		}
 
+		util.SetChartCacheDir(chartCacheDir)
+		util.SetChartVerification(verifyChart, chartKeyring)
+		util.SetChartIndexOptions(chartIndexTimeout, chartIndexTTL)
+		util.SetChartRepositoryInsecureSkipTLSVerify(insecureSkipTLSVerify)
+		if err := setChartRepositories(cmd); err != nil {
+			return err
+		}
+		if needsChartIndex(cmd) {
+			if err := loadChartVersions(cmd); err != nil {
+				return err
+			}
+		}
+
 		// Determine if synopsysctl is running in native command
 		nativeMode := strings.Contains(cmd.CommandPath(), "native")
+		// Chart, bundle, images and versions commands work without a cluster
+		for _, offlineCommand := range offlineCommands {
+			if strings.HasPrefix(cmd.CommandPath(), offlineCommand) {
+				nativeMode = true
+			}
+		}
 
 		// Don't set cluster resources if we are in native mode (aka the command doesn't need access the cluster)
 		// This allows users to use native when not connected to a cluster
@@ -104,6 +142,18 @@ func init() {
 	rootCmd.PersistentFlags().StringVar(&kubeConfigPath, "kubeconfig", kubeConfigPath, "Path to a kubeconfig file with the context set to a cluster for synopsysctl to access")
 	rootCmd.PersistentFlags().BoolVar(&insecureSkipTLSVerify, "insecure-skip-tls-verify", insecureSkipTLSVerify, "Server's certificate won't be validated. HTTPS will be less secure")
 	rootCmd.PersistentFlags().StringVarP(&logLevelCtl, "verbose-level", "v", logLevelCtl, "Log level for synopsysctl [trace|debug|info|warn|error|fatal|panic]")
+	rootCmd.PersistentFlags().StringSliceVar(&chartRepositoryURLs, "chart-repository", chartRepositoryURLs, "URL of a chart repository to find charts in, repeat to search several repositories in order (overrides chartRepositories in the config file)")
+	rootCmd.PersistentFlags().StringVar(&chartRepositoryUsername, "chart-repository-username", chartRepositoryUsername, "Username for basic authentication with the chart repositories")
+	rootCmd.PersistentFlags().StringVar(&chartRepositoryPassword, "chart-repository-password", chartRepositoryPassword, "Password for basic authentication with the chart repositories")
+	rootCmd.PersistentFlags().StringVar(&chartRepositoryToken, "chart-repository-token", chartRepositoryToken, "Bearer token for authentication with the chart repositories")
+	rootCmd.PersistentFlags().StringVar(&chartRepositoryCAFile, "chart-repository-ca-file", chartRepositoryCAFile, "Path to a PEM encoded CA bundle to verify the certificates of the chart repositories")
+	rootCmd.PersistentFlags().StringVar(&chartRepositoryCertFile, "chart-repository-cert-file", chartRepositoryCertFile, "Path to a PEM encoded client certificate to authenticate with the chart repositories")
+	rootCmd.PersistentFlags().StringVar(&chartRepositoryKeyFile, "chart-repository-key-file", chartRepositoryKeyFile, "Path to the PEM encoded key of the client certificate of the chart repositories")
+	rootCmd.PersistentFlags().BoolVar(&verifyChart, "verify", verifyChart, "Verify the signature and digest of a chart before using it, refusing charts that can't be verified")
+	rootCmd.PersistentFlags().StringVar(&chartKeyring, "keyring", chartKeyring, "PGP keyring with the public keys used to verify charts when --verify is set")
+	rootCmd.PersistentFlags().StringVar(&chartCacheDir, "chart-cache-dir", chartCacheDir, "Directory of the local chart cache used to find charts without network access")
+	rootCmd.PersistentFlags().DurationVar(&chartIndexTimeout, "chart-index-timeout", chartIndexTimeout, "How long to wait for the chart repositories' index before using the chart cache, 0 waits forever")
+	rootCmd.PersistentFlags().DurationVar(&chartIndexTTL, "chart-index-ttl", chartIndexTTL, "How long to reuse the chart repositories' index in the chart cache before downloading it again, 0 always downloads it")
 }
 
 // initConfig reads in config file and ENV variables if set.
@@ -0,0 +1,250 @@
+/*
+Copyright (C) 2019 Synopsys, Inc.
+
+Licensed to the Apache Software Foundation (ASF) under one
+or more contributor license agreements. See the NOTICE file
+distributed with this work for additional information
+regarding copyright ownership. The ASF licenses this file
+to you under the Apache License, Version 2.0 (the
+"License"); you may not use this file except in compliance
+with the License. You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing,
+software distributed under the License is distributed on an
+"AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+KIND, either express or implied. See the License for the
+specific language governing permissions and limitations
+under the License.
+*/
+
+package synopsysctl
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/blackducksoftware/synopsysctl/pkg/globals"
+	"github.com/blackducksoftware/synopsysctl/pkg/util"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// Scale Command Options and Defaults
+var scaleDryRun = false
+
+// scaleComponent is a component whose replicas can be scaled with a flag
+type scaleComponent struct {
+	flag    string
+	name    string
+	keyList []string
+}
+
+var blackDuckScaleComponents = []scaleComponent{
+	{flag: "jobrunner-replicas", name: "jobrunner", keyList: []string{"jobrunner", "replicas"}},
+	{flag: "scan-replicas", name: "scan", keyList: []string{"scan", "replicas"}},
+	{flag: "binaryscanner-replicas", name: "binaryscanner", keyList: []string{"binaryscanner", "replicas"}},
+}
+
+var opsSightScaleComponents = []scaleComponent{
+	{flag: "scannerpod-replica-count", name: "scanner", keyList: []string{"scanner", "replicas"}},
+}
+
+var bdbaScaleComponents = []scaleComponent{
+	{flag: "worker-replicas", name: "worker", keyList: []string{"worker", "replicas"}},
+	{flag: "frontend-replicas", name: "frontend", keyList: []string{"frontend", "web", "replicas"}},
+}
+
+// scaleChange is the change to the replicas of a component that is printed by --dry-run
+type scaleChange struct {
+	Component       string `json:"component"`
+	CurrentReplicas string `json:"currentReplicas,omitempty"`
+	NewReplicas     int    `json:"newReplicas"`
+}
+
+// getScaleChangesTable returns the table of the changes to the replicas of the components
+func getScaleChangesTable(changes []scaleChange) util.Table {
+	table := util.Table{Columns: []util.TableColumn{{Header: "COMPONENT"}, {Header: "CURRENT REPLICAS"}, {Header: "NEW REPLICAS"}}}
+	for _, change := range changes {
+		table.Rows = append(table.Rows, []string{change.Component, change.CurrentReplicas, strconv.Itoa(change.NewReplicas)})
+	}
+	return table
+}
+
+// getChartLocation returns the chart name of a product and the variable with its chart location
+func getChartLocation(product string) (string, *string) {
+	switch product {
+	case util.AlertName:
+		return globals.AlertChartName, &globals.AlertChartRepository
+	case util.BlackDuckName:
+		return globals.BlackDuckChartName, &globals.BlackDuckChartRepository
+	case util.OpsSightName:
+		return globals.OpsSightChartName, &globals.OpsSightChartRepository
+	case globals.PolarisName:
+		return globals.PolarisChartName, &globals.PolarisChartRepository
+	case globals.PolarisReportingName:
+		return globals.PolarisReportingChartName, &globals.PolarisReportingChartRepository
+	}
+	return globals.BDBAChartName, &globals.BDBAChartRepository
+}
+
+// scaleRelease sets the replicas of the components whose flags are set in the Helm values of the instance,
+// so that they are kept by later updates. With --dry-run, the changes are printed instead
+func scaleRelease(cmd *cobra.Command, product, name string, components []scaleComponent) error {
+	releaseName, versionKey := getReleaseNameAndVersionKey(product, name)
+	instance, err := util.GetWithHelm3(releaseName, namespace, kubeConfigPath)
+	if err != nil {
+		return fmt.Errorf("couldn't find instance %s in namespace %s", name, namespace)
+	}
+
+	flags := []string{}
+	scaledComponents := []string{}
+	changes := []scaleChange{}
+	for _, component := range components {
+		flags = append(flags, fmt.Sprintf("--%s", component.flag))
+		if !cmd.Flags().Lookup(component.flag).Changed {
+			continue
+		}
+		replicas, err := cmd.Flags().GetInt(component.flag)
+		if err != nil {
+			return err
+		}
+		if replicas < 0 {
+			return fmt.Errorf("--%s must be 0 or more, but got %d", component.flag, replicas)
+		}
+		change := scaleChange{Component: component.name, NewReplicas: replicas}
+		if value := util.GetValueFromRelease(instance, component.keyList); value != nil {
+			change.CurrentReplicas = fmt.Sprintf("%v", value)
+		}
+		changes = append(changes, change)
+		util.SetHelmValueInMap(instance.Config, component.keyList, replicas)
+		scaledComponents = append(scaledComponents, fmt.Sprintf("%s=%d", component.name, replicas))
+	}
+	if len(scaledComponents) == 0 {
+		cmd.Help()
+		return fmt.Errorf("at least one of %s must be set", strings.Join(flags, ", "))
+	}
+
+	if scaleDryRun {
+		return printOutput(cmd, changes, getScaleChangesTable(changes))
+	}
+
+	// Update the Helm Chart Location
+	chartName, chartRepository := getChartLocation(product)
+	err = SetHelmChartLocation(cmd.Flags(), chartName, getReleaseAppVersion(instance, versionKey), chartRepository)
+	if err != nil {
+		return fmt.Errorf("failed to set the app resources location due to %+v", err)
+	}
+
+	err = util.UpdateWithHelm3(releaseName, namespace, *chartRepository, instance.Config, kubeConfigPath)
+	if err != nil {
+		return fmt.Errorf("failed to scale %s resources: %+v", product, err)
+	}
+
+	if err := waitForInstance(cmd, product, name); err != nil {
+		return err
+	}
+
+	log.Infof("successfully submitted scale %s '%s' (%s) in namespace '%s'", product, name, strings.Join(scaledComponents, ", "), namespace)
+	return nil
+}
+
+// scaleCmd scales the components of a Synopsys resource
+var scaleCmd = &cobra.Command{
+	Use:   "scale",
+	Short: "Scale the components of a Synopsys resource",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return fmt.Errorf("must specify a sub-command")
+	},
+}
+
+// scaleBlackDuckCmd scales the components of a Black Duck instance
+var scaleBlackDuckCmd = &cobra.Command{
+	Use:           "blackduck NAME -n NAMESPACE",
+	Example:       "synopsysctl scale blackduck <name> -n <namespace> --jobrunner-replicas 3\nsynopsysctl scale blackduck <name> -n <namespace> --scan-replicas 2 --binaryscanner-replicas 2 --dry-run",
+	Short:         "Scale the components of a Black Duck instance",
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			cmd.Help()
+			return fmt.Errorf("this command takes 1 argument, but got %+v", args)
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return scaleRelease(cmd, util.BlackDuckName, args[0], blackDuckScaleComponents)
+	},
+}
+
+// scaleOpsSightCmd scales the components of an OpsSight instance
+var scaleOpsSightCmd = &cobra.Command{
+	Use:           "opssight NAME -n NAMESPACE",
+	Example:       "synopsysctl scale opssight <name> -n <namespace> --scannerpod-replica-count 3",
+	Short:         "Scale the components of an OpsSight instance",
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			cmd.Help()
+			return fmt.Errorf("this command takes 1 argument, but got %+v", args)
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return scaleRelease(cmd, util.OpsSightName, args[0], opsSightScaleComponents)
+	},
+}
+
+// scaleBDBACmd scales the components of a BDBA instance
+var scaleBDBACmd = &cobra.Command{
+	Use:           "bdba -n NAMESPACE",
+	Example:       "synopsysctl scale bdba -n <namespace> --worker-replicas 4\nsynopsysctl scale bdba -n <namespace> --worker-replicas 4 --frontend-replicas 2 --dry-run",
+	Short:         "Scale the components of a BDBA instance",
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 0 {
+			cmd.Help()
+			return fmt.Errorf("this command takes 0 arguments, but got %+v", args)
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return scaleRelease(cmd, globals.BDBAName, globals.BDBAName, bdbaScaleComponents)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(scaleCmd)
+	scaleCmd.PersistentFlags().BoolVar(&scaleDryRun, "dry-run", scaleDryRun, "Print the replicas that would change without scaling the instance")
+
+	scaleBlackDuckCmd.Flags().StringVarP(&namespace, "namespace", "n", namespace, "Namespace of the instance")
+	cobra.MarkFlagRequired(scaleBlackDuckCmd.Flags(), "namespace")
+	scaleBlackDuckCmd.Flags().Int("jobrunner-replicas", 1, "Number of job runner replicas")
+	scaleBlackDuckCmd.Flags().Int("scan-replicas", 1, "Number of scan replicas")
+	scaleBlackDuckCmd.Flags().Int("binaryscanner-replicas", 1, "Number of binary scanner replicas")
+	addChartLocationPathFlag(scaleBlackDuckCmd)
+	addWaitFlags(scaleBlackDuckCmd)
+	addOutputFlag(scaleBlackDuckCmd, util.OutputTable)
+	scaleCmd.AddCommand(scaleBlackDuckCmd)
+
+	scaleOpsSightCmd.Flags().StringVarP(&namespace, "namespace", "n", namespace, "Namespace of the instance")
+	cobra.MarkFlagRequired(scaleOpsSightCmd.Flags(), "namespace")
+	scaleOpsSightCmd.Flags().Int("scannerpod-replica-count", 1, "Number of Containers for scanning")
+	addChartLocationPathFlag(scaleOpsSightCmd)
+	addWaitFlags(scaleOpsSightCmd)
+	addOutputFlag(scaleOpsSightCmd, util.OutputTable)
+	scaleCmd.AddCommand(scaleOpsSightCmd)
+
+	scaleBDBACmd.Flags().StringVarP(&namespace, "namespace", "n", namespace, "Namespace of the instance")
+	cobra.MarkFlagRequired(scaleBDBACmd.Flags(), "namespace")
+	scaleBDBACmd.Flags().Int("worker-replicas", 1, "Number of worker replicas")
+	scaleBDBACmd.Flags().Int("frontend-replicas", 1, "Number of web application replicas")
+	addChartLocationPathFlag(scaleBDBACmd)
+	addWaitFlags(scaleBDBACmd)
+	addOutputFlag(scaleBDBACmd, util.OutputTable)
+	scaleCmd.AddCommand(scaleBDBACmd)
+}
@@ -0,0 +1,181 @@
+/*
+Copyright (C) 2019 Synopsys, Inc.
+
+Licensed to the Apache Software Foundation (ASF) under one
+or more contributor license agreements. See the NOTICE file
+distributed with this work for additional information
+regarding copyright ownership. The ASF licenses this file
+to you under the Apache License, Version 2.0 (the
+"License"); you may not use this file except in compliance
+with the License. You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing,
+software distributed under the License is distributed on an
+"AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+KIND, either express or implied. See the License for the
+specific language governing permissions and limitations
+under the License.
+*/
+
+package synopsysctl
+
+import (
+	"fmt"
+
+	"github.com/blackducksoftware/synopsysctl/pkg/util"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// Schedule Command Options and Defaults
+var scheduleStop = ""
+var scheduleStart = ""
+var scheduleImage = ""
+var scheduleAllNamespaces = false
+
+// scheduleCmd schedules the start and stop of an instance
+var scheduleCmd = &cobra.Command{
+	Use:           "schedule PRODUCT [NAME] -n NAMESPACE --image IMAGE",
+	Example:       "synopsysctl schedule blackduck <name> -n <namespace> --stop \"0 20 * * 1-5\" --start \"0 7 * * 1-5\" --image <synopsysctl image>\nsynopsysctl schedule polaris -n <namespace> --stop \"0 20 * * *\" --image <synopsysctl image>",
+	Short:         "Schedule the start and stop of an instance with cron jobs",
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	Args:          validateInstanceArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		product, name := getInstanceFromArgs(args)
+		if len(scheduleStop) == 0 && len(scheduleStart) == 0 {
+			cmd.Help()
+			return fmt.Errorf("at least one of --stop or --start must be set")
+		}
+
+		releaseName, _ := getReleaseNameAndVersionKey(product, name)
+		instance, err := util.GetWithHelm3(releaseName, namespace, kubeConfigPath)
+		if err != nil {
+			return fmt.Errorf("couldn't find instance %s in namespace %s", name, namespace)
+		}
+
+		schedules := []util.Schedule{}
+		for _, action := range []string{"stop", "start"} {
+			schedule := scheduleStop
+			if action == "start" {
+				schedule = scheduleStart
+			}
+			if len(schedule) == 0 {
+				continue
+			}
+			if err := util.ValidateCronSchedule(schedule); err != nil {
+				return fmt.Errorf("invalid --%s: %+v", action, err)
+			}
+			// the instance is stopped and started by scaling its workloads, so the cron jobs don't need the chart
+			// repository and keep the chart that the instance is deployed with
+			command := getInstanceCommand(action, product, name)
+			if util.IsExistInStringSlice(namedProducts, product) {
+				command = append(command, "--workloads")
+			}
+			schedules = append(schedules, util.Schedule{Action: action, Schedule: schedule, Command: command})
+		}
+
+		if err := util.ApplySchedules(kubeClient, namespace, product, name, scheduleImage, util.GetReleaseWorkloads(instance.Manifest), schedules); err != nil {
+			return fmt.Errorf("failed to schedule %s '%s' in namespace '%s' due to %+v", product, name, namespace, err)
+		}
+
+		for _, schedule := range schedules {
+			log.Infof("successfully scheduled %s %s '%s' in namespace '%s' at '%s'", schedule.Action, product, name, namespace, schedule.Schedule)
+		}
+		return nil
+	},
+}
+
+// scheduleListCmd lists the scheduled starts and stops of the instances
+var scheduleListCmd = &cobra.Command{
+	Use:           "list [PRODUCT [NAME]] -n NAMESPACE",
+	Example:       "synopsysctl schedule list -n <namespace>\nsynopsysctl schedule list blackduck <name> -n <namespace>\nsynopsysctl schedule list --all-namespaces",
+	Short:         "List the scheduled starts and stops of the instances",
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) > 2 {
+			cmd.Help()
+			return fmt.Errorf("this command takes up to 2 arguments, but got %+v", args)
+		}
+		if len(args) > 0 {
+			if _, err := getChartName(args[0]); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		listNamespace := namespace
+		if scheduleAllNamespaces {
+			listNamespace = ""
+		} else if len(namespace) == 0 {
+			return fmt.Errorf("a namespace is required, use --namespace or --all-namespaces")
+		}
+		product, name := "", ""
+		if len(args) > 0 {
+			product, name = getInstanceFromArgs(args)
+			if len(args) == 1 && util.IsExistInStringSlice(namedProducts, product) {
+				name = ""
+			}
+		}
+
+		schedules, err := util.ListSchedules(kubeClient, listNamespace, product, name)
+		if err != nil {
+			return err
+		}
+		if len(schedules) == 0 && isTableOutput(cmd) {
+			log.Infof("no schedules found")
+			return nil
+		}
+		table := util.Table{Columns: []util.TableColumn{{Header: "PRODUCT"}, {Header: "NAME"}, {Header: "NAMESPACE"}, {Header: "ACTION"}, {Header: "SCHEDULE"}, {Header: "LAST SCHEDULE"}}}
+		for _, schedule := range schedules {
+			table.Rows = append(table.Rows, []string{schedule.Product, schedule.Name, schedule.Namespace, schedule.Action, schedule.Schedule, schedule.LastSchedule})
+		}
+		return printOutput(cmd, schedules, table)
+	},
+}
+
+// scheduleRemoveCmd removes the scheduled starts and stops of an instance
+var scheduleRemoveCmd = &cobra.Command{
+	Use:           "remove PRODUCT [NAME] -n NAMESPACE",
+	Example:       "synopsysctl schedule remove blackduck <name> -n <namespace>\nsynopsysctl schedule remove polaris -n <namespace>",
+	Short:         "Remove the scheduled starts and stops of an instance",
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	Args:          validateInstanceArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		product, name := getInstanceFromArgs(args)
+		found, err := util.DeleteSchedules(kubeClient, namespace, product, name)
+		if err != nil {
+			return fmt.Errorf("failed to remove the schedules of %s '%s' in namespace '%s' due to %+v", product, name, namespace, err)
+		}
+		if !found {
+			return fmt.Errorf("%s '%s' in namespace '%s' doesn't have any schedules", product, name, namespace)
+		}
+		log.Infof("successfully removed the schedules of %s '%s' in namespace '%s'", product, name, namespace)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(scheduleCmd)
+
+	scheduleCmd.Flags().StringVarP(&namespace, "namespace", "n", namespace, "Namespace of the instance")
+	cobra.MarkFlagRequired(scheduleCmd.Flags(), "namespace")
+	scheduleCmd.Flags().StringVar(&scheduleStop, "stop", scheduleStop, "Cron schedule to stop the instance at, in the time zone of the cluster (e.g. \"0 20 * * 1-5\")")
+	scheduleCmd.Flags().StringVar(&scheduleStart, "start", scheduleStart, "Cron schedule to start the instance at after a scheduled stop, in the time zone of the cluster (e.g. \"0 7 * * 1-5\")")
+	scheduleCmd.Flags().StringVar(&scheduleImage, "image", scheduleImage, "Image with synopsysctl that the cron jobs run the stop and start commands with")
+	cobra.MarkFlagRequired(scheduleCmd.Flags(), "image")
+
+	scheduleListCmd.Flags().StringVarP(&namespace, "namespace", "n", namespace, "Namespace of the instances")
+	scheduleListCmd.Flags().BoolVarP(&scheduleAllNamespaces, "all-namespaces", "A", scheduleAllNamespaces, "List the schedules in all namespaces")
+	addOutputFlag(scheduleListCmd, util.OutputTable)
+	scheduleCmd.AddCommand(scheduleListCmd)
+
+	scheduleRemoveCmd.Flags().StringVarP(&namespace, "namespace", "n", namespace, "Namespace of the instance")
+	cobra.MarkFlagRequired(scheduleRemoveCmd.Flags(), "namespace")
+	scheduleCmd.AddCommand(scheduleRemoveCmd)
+}
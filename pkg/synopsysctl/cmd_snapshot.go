@@ -0,0 +1,157 @@
+/*
+Copyright (C) 2019 Synopsys, Inc.
+
+Licensed to the Apache Software Foundation (ASF) under one
+or more contributor license agreements. See the NOTICE file
+distributed with this work for additional information
+regarding copyright ownership. The ASF licenses this file
+to you under the Apache License, Version 2.0 (the
+"License"); you may not use this file except in compliance
+with the License. You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing,
+software distributed under the License is distributed on an
+"AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+KIND, either express or implied. See the License for the
+specific language governing permissions and limitations
+under the License.
+*/
+
+package synopsysctl
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/blackducksoftware/synopsysctl/pkg/util"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/dynamic"
+)
+
+// Snapshot Command Options and Defaults
+var snapshotSet = ""
+var snapshotTimeout = util.DefaultWaitTimeout
+
+// listInstanceSnapshots returns the volume snapshots of an instance, or of one set of them if set isn't empty
+func listInstanceSnapshots(dynamicClient dynamic.Interface, product, name, set string) ([]util.VolumeSnapshotSummary, error) {
+	resource, err := util.GetVolumeSnapshotResource(kubeClient)
+	if err != nil {
+		return nil, err
+	}
+	snapshots, err := util.ListVolumeSnapshots(dynamicClient, resource, namespace, util.GetSnapshotLabelSelector(product, name, set))
+	if err != nil {
+		return nil, err
+	}
+	summaries := []util.VolumeSnapshotSummary{}
+	for _, snapshot := range snapshots {
+		summaries = append(summaries, util.GetVolumeSnapshotSummary(snapshot))
+	}
+	return summaries, nil
+}
+
+// snapshotCmd manages the volume snapshots of an instance
+var snapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "List and restore the volume snapshots of a Synopsys resource",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return fmt.Errorf("must specify a sub-command")
+	},
+}
+
+// snapshotListCmd lists the volume snapshots of an instance
+var snapshotListCmd = &cobra.Command{
+	Use:           "list PRODUCT [NAME] -n NAMESPACE",
+	Example:       "synopsysctl snapshot list blackduck <name> -n <namespace>\nsynopsysctl snapshot list polaris -n <namespace> -o yaml",
+	Short:         "List the volume snapshots of an instance",
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	Args:          validateInstanceArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		product, name := getInstanceFromArgs(args)
+		dynamicClient, err := dynamic.NewForConfig(restconfig)
+		if err != nil {
+			return fmt.Errorf("error creating the dynamic client due to %+v", err)
+		}
+		snapshots, err := listInstanceSnapshots(dynamicClient, product, name, "")
+		if err != nil {
+			return err
+		}
+		if len(snapshots) == 0 && isTableOutput(cmd) {
+			log.Infof("no volume snapshots found")
+			return nil
+		}
+		table := util.Table{Columns: []util.TableColumn{{Header: "SET"}, {Header: "NAME"}, {Header: "PVC"}, {Header: "READY"}, {Header: "SIZE"}, {Header: "CREATED"}}}
+		for _, snapshot := range snapshots {
+			table.Rows = append(table.Rows, []string{snapshot.Set, snapshot.Name, snapshot.PVC, fmt.Sprintf("%t", snapshot.ReadyToUse), snapshot.RestoreSize, snapshot.Created})
+		}
+		return printOutput(cmd, snapshots, table)
+	},
+}
+
+// snapshotRestoreCmd recreates the PVCs of an instance from a set of volume snapshots
+var snapshotRestoreCmd = &cobra.Command{
+	Use:           "restore PRODUCT [NAME] -n NAMESPACE [--set SET]",
+	Example:       "synopsysctl snapshot restore blackduck <name> -n <namespace>\nsynopsysctl snapshot restore blackduck <name> -n <namespace> --set <set>",
+	Short:         "Recreate the PVCs of a stopped instance from a set of volume snapshots",
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	Args:          validateInstanceArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		product, name := getInstanceFromArgs(args)
+		dynamicClient, err := dynamic.NewForConfig(restconfig)
+		if err != nil {
+			return fmt.Errorf("error creating the dynamic client due to %+v", err)
+		}
+
+		// the latest set is restored by default
+		set := snapshotSet
+		if len(set) == 0 {
+			snapshots, err := listInstanceSnapshots(dynamicClient, product, name, "")
+			if err != nil {
+				return err
+			}
+			if len(snapshots) == 0 {
+				return fmt.Errorf("%s '%s' doesn't have any volume snapshots in namespace '%s'", product, name, namespace)
+			}
+			set = snapshots[len(snapshots)-1].Set
+		}
+
+		// the PVCs can only be replaced once the pods that use them are gone
+		pods, err := util.ListPodsWithLabels(kubeClient, namespace, getInstanceLabelSelector(product, name))
+		if err != nil {
+			return fmt.Errorf("unable to list the pods in namespace '%s' due to %+v", namespace, err)
+		}
+		for _, pod := range pods.Items {
+			if pod.Status.Phase != corev1.PodSucceeded && pod.Status.Phase != corev1.PodFailed {
+				return fmt.Errorf("%s '%s' has running pods, stop it with '%s' before restoring its volume snapshots", product, name, strings.Join(getInstanceCommand("stop", product, name), " "))
+			}
+		}
+
+		log.Infof("restoring the PVCs of %s '%s' in namespace '%s' from the volume snapshots in set '%s'...", product, name, namespace, set)
+		pvcNames, err := util.RestoreVolumeSnapshots(kubeClient, dynamicClient, namespace, product, name, set, snapshotTimeout)
+		if err != nil {
+			return fmt.Errorf("failed to restore the volume snapshots of %s '%s' due to %+v", product, name, err)
+		}
+		log.Infof("successfully restored PVCs %s of %s '%s' in namespace '%s', start it with '%s'", strings.Join(pvcNames, ", "), product, name, namespace, strings.Join(getInstanceCommand("start", product, name), " "))
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(snapshotCmd)
+
+	snapshotListCmd.Flags().StringVarP(&namespace, "namespace", "n", namespace, "Namespace of the instance")
+	cobra.MarkFlagRequired(snapshotListCmd.Flags(), "namespace")
+	addOutputFlag(snapshotListCmd, util.OutputTable)
+	snapshotCmd.AddCommand(snapshotListCmd)
+
+	snapshotRestoreCmd.Flags().StringVarP(&namespace, "namespace", "n", namespace, "Namespace of the instance")
+	cobra.MarkFlagRequired(snapshotRestoreCmd.Flags(), "namespace")
+	snapshotRestoreCmd.Flags().StringVar(&snapshotSet, "set", snapshotSet, "Set of volume snapshots to restore, from 'synopsysctl snapshot list' (default the latest set)")
+	snapshotRestoreCmd.Flags().DurationVar(&snapshotTimeout, "timeout", snapshotTimeout, "How long to wait for each PVC to be deleted before it is recreated")
+	snapshotCmd.AddCommand(snapshotRestoreCmd)
+}
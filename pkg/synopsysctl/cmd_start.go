@@ -59,6 +59,12 @@ var startAlertCmd = &cobra.Command{
 	RunE: func(cmd *cobra.Command, args []string) error {
 		alertName := args[0]
 		helmReleaseName := fmt.Sprintf("%s%s", alertName, globals.AlertPostSuffix)
+		if workloads, _ := cmd.Flags().GetBool("workloads"); workloads {
+			if err := startReleaseWorkloads(helmReleaseName); err != nil {
+				return err
+			}
+			return waitForInstance(cmd, util.AlertName, alertName)
+		}
 
 		instance, err := util.GetWithHelm3(helmReleaseName, namespace, kubeConfigPath)
 		if err != nil {
@@ -81,6 +87,10 @@ var startAlertCmd = &cobra.Command{
 			return fmt.Errorf("failed to create Alert resources: %+v", cleanErrorMsg)
 		}
 
+		if err := waitForInstance(cmd, util.AlertName, alertName); err != nil {
+			return err
+		}
+
 		log.Infof("successfully submitted start Alert '%s' in namespace '%s'", alertName, namespace)
 
 		return nil
@@ -102,25 +112,23 @@ var startBlackDuckCmd = &cobra.Command{
 		return nil
 	},
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if workloads, _ := cmd.Flags().GetBool("workloads"); workloads {
+			if err := startReleaseWorkloads(args[0]); err != nil {
+				return err
+			}
+			return waitForInstance(cmd, util.BlackDuckName, args[0])
+		}
 
 		instance, err := util.GetWithHelm3(args[0], namespace, kubeConfigPath)
 		if err != nil {
 			return fmt.Errorf("couldn't find instance %s in namespace %s", args[0], namespace)
 		}
-
-		// Update the Helm Chart Location
-		blackDuckVersionFromRelease := util.GetValueFromRelease(instance, []string{"imageTag"}).(string)
-		err = SetHelmChartLocation(cmd.Flags(), globals.BlackDuckChartName, blackDuckVersionFromRelease, &globals.BlackDuckChartRepository)
-		if err != nil {
-			return fmt.Errorf("failed to set the app resources location due to %+v", err)
+		if err := setBlackDuckStatus(cmd.Flags(), instance, "Running"); err != nil {
+			return err
 		}
 
-		helmValuesMap := instance.Config
-		util.SetHelmValueInMap(helmValuesMap, []string{"status"}, "Running")
-
-		err = util.UpdateWithHelm3(args[0], namespace, globals.BlackDuckChartRepository, helmValuesMap, kubeConfigPath)
-		if err != nil {
-			return fmt.Errorf("failed to create Blackduck resources: %+v", err)
+		if err := waitForInstance(cmd, util.BlackDuckName, args[0]); err != nil {
+			return err
 		}
 		return nil
 	},
@@ -142,6 +150,12 @@ var startOpsSightCmd = &cobra.Command{
 	},
 	RunE: func(cmd *cobra.Command, args []string) error {
 		opssightName := args[0]
+		if workloads, _ := cmd.Flags().GetBool("workloads"); workloads {
+			if err := startReleaseWorkloads(opssightName); err != nil {
+				return err
+			}
+			return waitForInstance(cmd, util.OpsSightName, opssightName)
+		}
 		instance, err := util.GetWithHelm3(opssightName, namespace, kubeConfigPath)
 		if err != nil {
 			return fmt.Errorf("couldn't find instance %s in namespace %s", opssightName, namespace)
@@ -161,6 +175,107 @@ var startOpsSightCmd = &cobra.Command{
 		if err != nil {
 			return fmt.Errorf("failed to create OpsSight resources: %+v", err)
 		}
+
+		if err := waitForInstance(cmd, util.OpsSightName, opssightName); err != nil {
+			return err
+		}
+		return nil
+	},
+}
+
+// startReleaseWorkloads starts an instance of a product that was stopped by stopReleaseWorkloads, e.g. by a
+// scheduled stop, by restoring the replicas of its Deployments and StatefulSets
+func startReleaseWorkloads(releaseName string) error {
+	instance, err := util.GetWithHelm3(releaseName, namespace, kubeConfigPath)
+	if err != nil {
+		return fmt.Errorf("couldn't find instance %s in namespace %s", releaseName, namespace)
+	}
+	if err := util.StartReleaseWorkloads(kubeClient, namespace, instance.Manifest); err != nil {
+		return fmt.Errorf("failed to start %s resources: %+v", releaseName, err)
+	}
+	return nil
+}
+
+// startPolarisCmd starts a Polaris instance
+var startPolarisCmd = &cobra.Command{
+	Use:           "polaris -n NAMESPACE",
+	Example:       "synopsysctl start polaris -n <namespace>",
+	Short:         "Start a Polaris instance",
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 0 {
+			cmd.Help()
+			return fmt.Errorf("this command takes 0 arguments, but got %+v", args)
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := startReleaseWorkloads(globals.PolarisName); err != nil {
+			return err
+		}
+
+		if err := waitForInstance(cmd, globals.PolarisName, globals.PolarisName); err != nil {
+			return err
+		}
+
+		log.Infof("successfully submitted start Polaris in namespace '%s'", namespace)
+		return nil
+	},
+}
+
+// startPolarisReportingCmd starts a Polaris Reporting instance
+var startPolarisReportingCmd = &cobra.Command{
+	Use:           "polaris-reporting -n NAMESPACE",
+	Example:       "synopsysctl start polaris-reporting -n <namespace>",
+	Short:         "Start a Polaris Reporting instance",
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 0 {
+			cmd.Help()
+			return fmt.Errorf("this command takes 0 arguments, but got %+v", args)
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := startReleaseWorkloads(globals.PolarisReportingName); err != nil {
+			return err
+		}
+
+		if err := waitForInstance(cmd, globals.PolarisReportingName, globals.PolarisReportingName); err != nil {
+			return err
+		}
+
+		log.Infof("successfully submitted start Polaris Reporting in namespace '%s'", namespace)
+		return nil
+	},
+}
+
+// startBDBACmd starts a BDBA instance
+var startBDBACmd = &cobra.Command{
+	Use:           "bdba -n NAMESPACE",
+	Example:       "synopsysctl start bdba -n <namespace>",
+	Short:         "Start a BDBA instance",
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 0 {
+			cmd.Help()
+			return fmt.Errorf("this command takes 0 arguments, but got %+v", args)
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := startReleaseWorkloads(globals.BDBAName); err != nil {
+			return err
+		}
+
+		if err := waitForInstance(cmd, globals.BDBAName, globals.BDBAName); err != nil {
+			return err
+		}
+
+		log.Infof("successfully submitted start BDBA in namespace '%s'", namespace)
 		return nil
 	},
 }
@@ -173,15 +288,36 @@ func init() {
 	startAlertCmd.Flags().StringVarP(&namespace, "namespace", "n", namespace, "Namespace of the instance(s)")
 	cobra.MarkFlagRequired(startAlertCmd.Flags(), "namespace")
 	addChartLocationPathFlag(startAlertCmd)
+	addWorkloadsFlag(startAlertCmd)
+	addWaitFlags(startAlertCmd)
 	startCmd.AddCommand(startAlertCmd)
 
 	startBlackDuckCmd.Flags().StringVarP(&namespace, "namespace", "n", namespace, "Namespace of the instance(s)")
 	cobra.MarkFlagRequired(startBlackDuckCmd.Flags(), "namespace")
 	addChartLocationPathFlag(startBlackDuckCmd)
+	addWorkloadsFlag(startBlackDuckCmd)
+	addWaitFlags(startBlackDuckCmd)
 	startCmd.AddCommand(startBlackDuckCmd)
 
 	startOpsSightCmd.Flags().StringVarP(&namespace, "namespace", "n", namespace, "Namespace of the instance(s)")
 	cobra.MarkFlagRequired(startOpsSightCmd.PersistentFlags(), "namespace")
 	addChartLocationPathFlag(startOpsSightCmd)
+	addWorkloadsFlag(startOpsSightCmd)
+	addWaitFlags(startOpsSightCmd)
 	startCmd.AddCommand(startOpsSightCmd)
+
+	startPolarisCmd.Flags().StringVarP(&namespace, "namespace", "n", namespace, "Namespace of the instance(s)")
+	cobra.MarkFlagRequired(startPolarisCmd.Flags(), "namespace")
+	addWaitFlags(startPolarisCmd)
+	startCmd.AddCommand(startPolarisCmd)
+
+	startPolarisReportingCmd.Flags().StringVarP(&namespace, "namespace", "n", namespace, "Namespace of the instance(s)")
+	cobra.MarkFlagRequired(startPolarisReportingCmd.Flags(), "namespace")
+	addWaitFlags(startPolarisReportingCmd)
+	startCmd.AddCommand(startPolarisReportingCmd)
+
+	startBDBACmd.Flags().StringVarP(&namespace, "namespace", "n", namespace, "Namespace of the instance(s)")
+	cobra.MarkFlagRequired(startBDBACmd.Flags(), "namespace")
+	addWaitFlags(startBDBACmd)
+	startCmd.AddCommand(startBDBACmd)
 }
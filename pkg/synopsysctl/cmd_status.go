@@ -0,0 +1,175 @@
+/*
+Copyright (C) 2019 Synopsys, Inc.
+
+Licensed to the Apache Software Foundation (ASF) under one
+or more contributor license agreements. See the NOTICE file
+distributed with this work for additional information
+regarding copyright ownership. The ASF licenses this file
+to you under the Apache License, Version 2.0 (the
+"License"); you may not use this file except in compliance
+with the License. You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing,
+software distributed under the License is distributed on an
+"AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+KIND, either express or implied. See the License for the
+specific language governing permissions and limitations
+under the License.
+*/
+
+package synopsysctl
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	blackduckutil "github.com/blackducksoftware/synopsysctl/pkg/blackduck/util"
+	"github.com/blackducksoftware/synopsysctl/pkg/util"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// instanceStatus is the health of an instance of a product
+type instanceStatus struct {
+	Product      string            `json:"product"`
+	Name         string            `json:"name"`
+	Namespace    string            `json:"namespace"`
+	Version      string            `json:"version"`
+	ChartVersion string            `json:"chartVersion"`
+	Revision     int               `json:"revision"`
+	State        string            `json:"state"`
+	URL          string            `json:"url,omitempty"`
+	Pods         []util.PodSummary `json:"pods"`
+	PVCs         []util.PVCSummary `json:"pvcs"`
+}
+
+// getInstanceStatus returns the health of an instance of a product
+func getInstanceStatus(product, name string) (*instanceStatus, error) {
+	releaseName, versionKey := getReleaseNameAndVersionKey(product, name)
+	helmRelease, err := util.GetWithHelm3(releaseName, namespace, kubeConfigPath)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't find instance %s in namespace %s", name, namespace)
+	}
+	status := &instanceStatus{
+		Product:      product,
+		Name:         name,
+		Namespace:    namespace,
+		Version:      getReleaseAppVersion(helmRelease, versionKey),
+		ChartVersion: helmRelease.Chart.Metadata.Version,
+		Revision:     helmRelease.Version,
+		Pods:         []util.PodSummary{},
+		PVCs:         []util.PVCSummary{},
+	}
+	values := util.GetReleaseValues(helmRelease)
+	status.State = getReleaseState(product, helmRelease, values)
+	status.URL = getInstanceURL(product, name, values)
+
+	labelSelector := getInstanceLabelSelector(product, name)
+	pods, err := util.ListPodsWithLabels(kubeClient, namespace, labelSelector)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the pods of %s '%s' due to %+v", product, name, err)
+	}
+	for _, pod := range pods.Items {
+		status.Pods = append(status.Pods, util.GetPodSummary(pod))
+	}
+	pvcs, err := util.ListPVCs(kubeClient, namespace, labelSelector)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the persistent volume claims of %s '%s' due to %+v", product, name, err)
+	}
+	for _, pvc := range pvcs.Items {
+		status.PVCs = append(status.PVCs, util.GetPVCSummary(pvc))
+	}
+	return status, nil
+}
+
+// getInstanceURL returns the URL of the UI of an instance from its exposed service or route, it is
+// <pending> until the load balancer has an address
+func getInstanceURL(product, name string, values map[string]interface{}) string {
+	if exposeUI, ok := values["exposeui"].(bool); !ok || !exposeUI {
+		return ""
+	}
+	var serviceName, routeName string
+	switch product {
+	case util.AlertName:
+		serviceName = util.GetResourceName(name, util.AlertName, "exposed")
+		routeName = util.GetResourceName(name, util.AlertName, "")
+	case util.BlackDuckName:
+		serviceName = util.GetResourceName(name, util.BlackDuckName, "webserver-exposed")
+		routeName = util.GetResourceName(name, util.BlackDuckName, "")
+	default:
+		return ""
+	}
+
+	switch values["exposedServiceType"] {
+	case "LoadBalancer":
+		ipAddress, err := blackduckutil.GetLoadBalancerIPAddress(kubeClient, namespace, serviceName)
+		if err != nil || len(ipAddress) == 0 {
+			log.Debugf("the load balancer of %s '%s' doesn't have an address: %+v", product, name, err)
+			return "<pending>"
+		}
+		if service, err := util.GetService(kubeClient, namespace, serviceName); err == nil && len(service.Spec.Ports) > 0 {
+			return fmt.Sprintf("https://%s:%d", ipAddress, service.Spec.Ports[0].Port)
+		}
+		return fmt.Sprintf("https://%s", ipAddress)
+	case "NodePort":
+		nodePorts, err := blackduckutil.GetNodePortIPAddress(kubeClient, namespace, serviceName)
+		if err != nil || len(nodePorts) == 0 {
+			log.Debugf("unable to get the node port of %s '%s': %+v", product, name, err)
+			return ""
+		}
+		return fmt.Sprintf("https://%s", strings.Split(nodePorts, ",")[0])
+	case "OpenShift":
+		route, err := util.GetRoute(util.GetRouteClient(restconfig, kubeClient, namespace), namespace, routeName)
+		if err != nil {
+			log.Debugf("unable to get the route of %s '%s': %+v", product, name, err)
+			return ""
+		}
+		return fmt.Sprintf("https://%s", route.Spec.Host)
+	}
+	return ""
+}
+
+// getInstanceStatusTables returns the tables of the instance, its pods and its volumes
+func getInstanceStatusTables(status *instanceStatus) []util.Table {
+	instanceTable := util.Table{
+		Columns: []util.TableColumn{{Header: "PRODUCT"}, {Header: "NAME"}, {Header: "NAMESPACE"}, {Header: "VERSION"}, {Header: "CHART VERSION"}, {Header: "REVISION"}, {Header: "STATE"}, {Header: "URL"}},
+		Rows:    [][]string{{status.Product, status.Name, status.Namespace, status.Version, status.ChartVersion, strconv.Itoa(status.Revision), status.State, status.URL}},
+	}
+	podTable := util.Table{Columns: []util.TableColumn{{Header: "COMPONENT"}, {Header: "POD"}, {Header: "READY"}, {Header: "STATUS"}, {Header: "RESTARTS"}, {Header: "NODE", Wide: true}}}
+	for _, pod := range status.Pods {
+		podTable.Rows = append(podTable.Rows, []string{pod.Component, pod.Name, pod.Ready, pod.Status, fmt.Sprintf("%d", pod.Restarts), pod.Node})
+	}
+	pvcTable := util.Table{Columns: []util.TableColumn{{Header: "PVC"}, {Header: "STATUS"}, {Header: "CAPACITY"}, {Header: "STORAGE CLASS"}}}
+	for _, pvc := range status.PVCs {
+		pvcTable.Rows = append(pvcTable.Rows, []string{pvc.Name, pvc.Status, pvc.Capacity, pvc.StorageClass})
+	}
+	return []util.Table{instanceTable, podTable, pvcTable}
+}
+
+// statusCmd shows the health of an instance
+var statusCmd = &cobra.Command{
+	Use:           "status PRODUCT [NAME] -n NAMESPACE",
+	Example:       "synopsysctl status blackduck <name> -n <namespace>\nsynopsysctl status alert <name> -n <namespace> -o json\nsynopsysctl status polaris -n <namespace>",
+	Short:         "Show the version, state, pods, volumes and URL of an instance",
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	Args:          validateInstanceArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		status, err := getInstanceStatus(getInstanceFromArgs(args))
+		if err != nil {
+			return err
+		}
+		return printOutput(cmd, status, getInstanceStatusTables(status)...)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(statusCmd)
+
+	statusCmd.Flags().StringVarP(&namespace, "namespace", "n", namespace, "Namespace of the instance")
+	cobra.MarkFlagRequired(statusCmd.Flags(), "namespace")
+	addOutputFlag(statusCmd, util.OutputTable)
+}
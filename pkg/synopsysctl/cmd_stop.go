@@ -59,6 +59,9 @@ var stopAlertCmd = &cobra.Command{
 	RunE: func(cmd *cobra.Command, args []string) error {
 		alertName := args[0]
 		helmReleaseName := fmt.Sprintf("%s%s", alertName, globals.AlertPostSuffix)
+		if workloads, _ := cmd.Flags().GetBool("workloads"); workloads {
+			return stopReleaseWorkloads(helmReleaseName)
+		}
 
 		instance, err := util.GetWithHelm3(helmReleaseName, namespace, kubeConfigPath)
 		if err != nil {
@@ -102,24 +105,15 @@ var stopBlackDuckCmd = &cobra.Command{
 		return nil
 	},
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if workloads, _ := cmd.Flags().GetBool("workloads"); workloads {
+			return stopReleaseWorkloads(args[0])
+		}
 		instance, err := util.GetWithHelm3(args[0], namespace, kubeConfigPath)
 		if err != nil {
 			return fmt.Errorf("couldn't find instance %s in namespace %s", args[0], namespace)
 		}
-
-		// Update the Helm Chart Location
-		blackDuckVersionFromRelease := util.GetValueFromRelease(instance, []string{"imageTag"}).(string)
-		err = SetHelmChartLocation(cmd.Flags(), globals.BlackDuckChartName, blackDuckVersionFromRelease, &globals.BlackDuckChartRepository)
-		if err != nil {
-			return fmt.Errorf("failed to set the app resources location due to %+v", err)
-		}
-
-		helmValuesMap := instance.Config
-		util.SetHelmValueInMap(helmValuesMap, []string{"status"}, "Stopped")
-
-		err = util.UpdateWithHelm3(args[0], namespace, globals.BlackDuckChartRepository, helmValuesMap, kubeConfigPath)
-		if err != nil {
-			return fmt.Errorf("failed to create Blackduck resources: %+v", err)
+		if err := setBlackDuckStatus(cmd.Flags(), instance, "Stopped"); err != nil {
+			return err
 		}
 		return nil
 	},
@@ -141,6 +135,9 @@ var stopOpsSightCmd = &cobra.Command{
 	},
 	RunE: func(cmd *cobra.Command, args []string) error {
 		opssightName := args[0]
+		if workloads, _ := cmd.Flags().GetBool("workloads"); workloads {
+			return stopReleaseWorkloads(opssightName)
+		}
 		instance, err := util.GetWithHelm3(opssightName, namespace, kubeConfigPath)
 		if err != nil {
 			return fmt.Errorf("couldn't find instance %s in namespace %s", opssightName, namespace)
@@ -164,6 +161,91 @@ var stopOpsSightCmd = &cobra.Command{
 	},
 }
 
+// stopReleaseWorkloads stops an instance of a product whose chart can't stop it, or an instance stopped with --workloads,
+// by scaling its Deployments and StatefulSets to 0 replicas. Their replicas are kept in an annotation for startReleaseWorkloads
+func stopReleaseWorkloads(releaseName string) error {
+	instance, err := util.GetWithHelm3(releaseName, namespace, kubeConfigPath)
+	if err != nil {
+		return fmt.Errorf("couldn't find instance %s in namespace %s", releaseName, namespace)
+	}
+	if err := util.StopReleaseWorkloads(kubeClient, namespace, instance.Manifest); err != nil {
+		return fmt.Errorf("failed to stop %s resources: %+v", releaseName, err)
+	}
+	return nil
+}
+
+// stopPolarisCmd stops a Polaris instance
+var stopPolarisCmd = &cobra.Command{
+	Use:           "polaris -n NAMESPACE",
+	Example:       "synopsysctl stop polaris -n <namespace>",
+	Short:         "Stop a Polaris instance",
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 0 {
+			cmd.Help()
+			return fmt.Errorf("this command takes 0 arguments, but got %+v", args)
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := stopReleaseWorkloads(globals.PolarisName); err != nil {
+			return err
+		}
+
+		log.Infof("successfully submitted stop Polaris in namespace '%s'", namespace)
+		return nil
+	},
+}
+
+// stopPolarisReportingCmd stops a Polaris Reporting instance
+var stopPolarisReportingCmd = &cobra.Command{
+	Use:           "polaris-reporting -n NAMESPACE",
+	Example:       "synopsysctl stop polaris-reporting -n <namespace>",
+	Short:         "Stop a Polaris Reporting instance",
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 0 {
+			cmd.Help()
+			return fmt.Errorf("this command takes 0 arguments, but got %+v", args)
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := stopReleaseWorkloads(globals.PolarisReportingName); err != nil {
+			return err
+		}
+
+		log.Infof("successfully submitted stop Polaris Reporting in namespace '%s'", namespace)
+		return nil
+	},
+}
+
+// stopBDBACmd stops a BDBA instance
+var stopBDBACmd = &cobra.Command{
+	Use:           "bdba -n NAMESPACE",
+	Example:       "synopsysctl stop bdba -n <namespace>",
+	Short:         "Stop a BDBA instance",
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 0 {
+			cmd.Help()
+			return fmt.Errorf("this command takes 0 arguments, but got %+v", args)
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := stopReleaseWorkloads(globals.BDBAName); err != nil {
+			return err
+		}
+
+		log.Infof("successfully submitted stop BDBA in namespace '%s'", namespace)
+		return nil
+	},
+}
+
 func init() {
 	stopAlertCobraHelper = *alertctl.NewHelmValuesFromCobraFlags()
 
@@ -172,15 +254,30 @@ func init() {
 	stopAlertCmd.Flags().StringVarP(&namespace, "namespace", "n", namespace, "Namespace of the instance(s)")
 	cobra.MarkFlagRequired(stopAlertCmd.Flags(), "namespace")
 	addChartLocationPathFlag(stopAlertCmd)
+	addWorkloadsFlag(stopAlertCmd)
 	stopCmd.AddCommand(stopAlertCmd)
 
 	stopBlackDuckCmd.Flags().StringVarP(&namespace, "namespace", "n", namespace, "Namespace of the instance(s)")
 	cobra.MarkFlagRequired(stopBlackDuckCmd.Flags(), "namespace")
 	addChartLocationPathFlag(stopBlackDuckCmd)
+	addWorkloadsFlag(stopBlackDuckCmd)
 	stopCmd.AddCommand(stopBlackDuckCmd)
 
 	stopOpsSightCmd.Flags().StringVarP(&namespace, "namespace", "n", namespace, "Namespace of the instance(s)")
 	cobra.MarkFlagRequired(stopOpsSightCmd.PersistentFlags(), "namespace")
 	addChartLocationPathFlag(stopOpsSightCmd)
+	addWorkloadsFlag(stopOpsSightCmd)
 	stopCmd.AddCommand(stopOpsSightCmd)
+
+	stopPolarisCmd.Flags().StringVarP(&namespace, "namespace", "n", namespace, "Namespace of the instance(s)")
+	cobra.MarkFlagRequired(stopPolarisCmd.Flags(), "namespace")
+	stopCmd.AddCommand(stopPolarisCmd)
+
+	stopPolarisReportingCmd.Flags().StringVarP(&namespace, "namespace", "n", namespace, "Namespace of the instance(s)")
+	cobra.MarkFlagRequired(stopPolarisReportingCmd.Flags(), "namespace")
+	stopCmd.AddCommand(stopPolarisReportingCmd)
+
+	stopBDBACmd.Flags().StringVarP(&namespace, "namespace", "n", namespace, "Namespace of the instance(s)")
+	cobra.MarkFlagRequired(stopBDBACmd.Flags(), "namespace")
+	stopCmd.AddCommand(stopBDBACmd)
 }
@@ -62,6 +62,12 @@ var updatePolarisCobraHelper polaris.HelmValuesFromCobraFlags
 var updatePolarisReportingCobraHelper polarisreporting.HelmValuesFromCobraFlags
 var updateBDBACobraHelper bdba.HelmValuesFromCobraFlags
 
+// Update Black Duck Command Options and Defaults
+var updateBlackDuckCheckpoint = ""
+var updateBlackDuckCheckpointTo = ""
+var updateBlackDuckNoCheckpoint = false
+var updateBlackDuckCheckpointTimeout = 30 * time.Minute
+
 // updateCmd provides functionality to update/upgrade features of
 // Synopsys resources
 var updateCmd = &cobra.Command{
@@ -91,6 +97,9 @@ var updateAlertCmd = &cobra.Command{
 		return nil
 	},
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := setVersionFromBundle(cmd.Flags()); err != nil {
+			return err
+		}
 		alertName := args[0]
 		helmReleaseName := fmt.Sprintf("%s%s", alertName, globals.AlertPostSuffix)
 
@@ -123,6 +132,9 @@ var updateAlertCmd = &cobra.Command{
 			}
 			err = updateAlertHelmBased(cmd, helmReleaseName, alertName)
 		} else if isOperatorBased {
+			if diff, _ := cmd.Flags().GetBool("diff"); diff {
+				return fmt.Errorf("--diff is not supported for an Alert instance that was deployed by the Synopsys Operator")
+			}
 			versionFlag := cmd.Flag("version")
 			if !versionFlag.Changed {
 				return fmt.Errorf("you must upgrade this Alert version with --version to use this synopsysctl binary")
@@ -140,6 +152,13 @@ var updateAlertCmd = &cobra.Command{
 		if err != nil {
 			return err
 		}
+		if diff, _ := cmd.Flags().GetBool("diff"); diff {
+			return nil
+		}
+
+		if err := waitForInstance(cmd, util.AlertName, alertName); err != nil {
+			return err
+		}
 
 		log.Infof("Alert has been successfully Updated in namespace '%s'!", namespace)
 
@@ -154,7 +173,12 @@ func updateAlertHelmBased(cmd *cobra.Command, helmReleaseName string, alertName
 		cleanErrorMsg := cleanAlertHelmError(err.Error(), helmReleaseName, alertName)
 		return fmt.Errorf("failed to get previous user defined values: %+v", cleanErrorMsg)
 	}
-	updateAlertCobraHelper.SetArgs(helmRelease.Config)
+	// Set the values from the --values files over the current values, the flags take precedence over them
+	valuesFromFiles, err := mergeValuesFiles(cmd.Flags(), helmRelease.Config)
+	if err != nil {
+		return err
+	}
+	updateAlertCobraHelper.SetArgs(valuesFromFiles)
 
 	// Update Helm Values with flags
 	helmValuesMap, err := updateAlertCobraHelper.GenerateHelmFlagsFromCobraFlags(cmd.Flags())
@@ -197,6 +221,7 @@ func updateAlertHelmBased(cmd *cobra.Command, helmReleaseName string, alertName
 	}
 
 	// Get secrets for Alert
+	secrets := []corev1.Secret{}
 	certificateFlag := cmd.Flag("certificate-file-path")
 	certificateKeyFlag := cmd.Flag("certificate-key-file-path")
 	if certificateFlag.Changed && certificateKeyFlag.Changed {
@@ -212,15 +237,7 @@ func updateAlertHelmBased(cmd *cobra.Command, helmReleaseName string, alertName
 		customCertificateSecretName := "alert-custom-certificate"
 		customCertificateSecret := alert.GetAlertCustomCertificateSecret(namespace, customCertificateSecretName, certificateData, certificateKeyData)
 		util.SetHelmValueInMap(helmValuesMap, []string{"webserverCustomCertificatesSecretName"}, customCertificateSecretName)
-		if _, err := kubeClient.CoreV1().Secrets(namespace).Create(&customCertificateSecret); err != nil {
-			if k8serrors.IsAlreadyExists(err) {
-				if _, err := kubeClient.CoreV1().Secrets(namespace).Update(&customCertificateSecret); err != nil {
-					return fmt.Errorf("failed to update certificate secret: %+v", err)
-				}
-			} else {
-				return fmt.Errorf("failed to create certificate secret: %+v", err)
-			}
-		}
+		secrets = append(secrets, customCertificateSecret)
 	}
 	javaKeystoreFlag := cmd.Flag("java-keystore-file-path")
 	if javaKeystoreFlag.Changed {
@@ -231,13 +248,30 @@ func updateAlertHelmBased(cmd *cobra.Command, helmReleaseName string, alertName
 		javaKeystoreSecretName := "alert-java-keystore"
 		javaKeystoreSecret := alert.GetAlertJavaKeystoreSecret(namespace, javaKeystoreSecretName, javaKeystoreData)
 		util.SetHelmValueInMap(helmValuesMap, []string{"javaKeystoreSecretName"}, javaKeystoreSecretName)
-		if _, err := kubeClient.CoreV1().Secrets(namespace).Create(&javaKeystoreSecret); err != nil {
+		secrets = append(secrets, javaKeystoreSecret)
+	}
+
+	// Show the changes instead of updating Alert
+	if diff, _ := cmd.Flags().GetBool("diff"); diff {
+		return printUpdateDiff(helmReleaseName, namespace, globals.AlertChartRepository, helmValuesMap)
+	}
+
+	if err := snapshotInstance(cmd, util.AlertName, alertName); err != nil {
+		return err
+	}
+
+	for _, secret := range secrets {
+		// Save the current secret so that it can be restored by a rollback
+		if err := util.SaveSecretRevision(kubeClient, namespace, helmReleaseName, secret.Name, helmRelease.Version); err != nil {
+			return err
+		}
+		if _, err := kubeClient.CoreV1().Secrets(namespace).Create(&secret); err != nil {
 			if k8serrors.IsAlreadyExists(err) {
-				if _, err := kubeClient.CoreV1().Secrets(namespace).Update(&javaKeystoreSecret); err != nil {
-					return fmt.Errorf("failed to update javakeystore secret: %+v", err)
+				if _, err := kubeClient.CoreV1().Secrets(namespace).Update(&secret); err != nil {
+					return fmt.Errorf("failed to update secret %s: %+v", secret.Name, err)
 				}
 			} else {
-				return fmt.Errorf("failed to create javakeystore secret: %+v", err)
+				return fmt.Errorf("failed to create secret %s: %+v", secret.Name, err)
 			}
 		}
 	}
@@ -281,6 +315,9 @@ func updateAlertOperatorBased(cmd *cobra.Command, newReleaseName string, alertNa
 		return fmt.Errorf("error getting Alert '%s' in namespace '%s' due to %+v", alertName, crdNamespace, err)
 	}
 
+	if err := snapshotInstance(cmd, util.AlertName, alertName); err != nil {
+		return err
+	}
 	if err := migrateAlert(currAlert, newReleaseName, operatorNamespace, crdNamespace, cmd.Flags()); err != nil {
 		// TODO restart operator if migration failed?
 		return err
@@ -291,7 +328,7 @@ func updateAlertOperatorBased(cmd *cobra.Command, newReleaseName string, alertNa
 // updateBlackDuckCmd updates a Black Duck instance
 var updateBlackDuckCmd = &cobra.Command{
 	Use:           "blackduck NAME -n NAMESPACE",
-	Example:       "synopsyctl update blackduck <name> -n <namespace> --size medium",
+	Example:       "synopsyctl update blackduck <name> -n <namespace> --size medium\nsynopsyctl update blackduck <name> -n <namespace> --size medium --diff\nsynopsyctl update blackduck <name> -n <namespace> --version <version> --checkpoint all --checkpoint-to pvc:<claim name>",
 	Short:         "Update a Black Duck instance",
 	SilenceUsage:  true,
 	SilenceErrors: true,
@@ -302,6 +339,13 @@ var updateBlackDuckCmd = &cobra.Command{
 		return nil
 	},
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := setVersionFromBundle(cmd.Flags()); err != nil {
+			return err
+		}
+		checkpointPolicy, checkpointTo, err := getCheckpointOptions(cmd.Flags())
+		if err != nil {
+			return err
+		}
 		blackDuckName := args[0]
 		blackDuckNamespace := namespace
 
@@ -355,7 +399,12 @@ var updateBlackDuckCmd = &cobra.Command{
 				instance.Config = util.MergeMaps(instance.Config, sizeValuesFromChart)
 			}
 
-			updateBlackDuckCobraHelper.SetArgs(instance.Config)
+			// Set the values from the --values files over the current values, the flags take precedence over them
+			valuesFromFiles, err := mergeValuesFiles(cmd.Flags(), instance.Config)
+			if err != nil {
+				return err
+			}
+			updateBlackDuckCobraHelper.SetArgs(valuesFromFiles)
 			helmValuesMap, err := updateBlackDuckCobraHelper.GenerateHelmFlagsFromCobraFlags(cmd.Flags())
 			if err != nil {
 				return err
@@ -365,16 +414,48 @@ var updateBlackDuckCmd = &cobra.Command{
 			if err != nil {
 				return err
 			}
+
+			// Show the changes instead of updating Black Duck
+			if diff, _ := cmd.Flags().GetBool("diff"); diff {
+				return printUpdateDiff(blackDuckName, blackDuckNamespace, globals.BlackDuckChartRepository, helmValuesMap)
+			}
+
+			if err := snapshotInstance(cmd, util.BlackDuckName, blackDuckName); err != nil {
+				return err
+			}
+
+			// Take a checkpoint before a version change because the upgrade can migrate the database
+			var checkpoint *blackDuckCheckpoint
+			currentRevision := instance.Version
+			if cmd.Flag("version").Changed && globals.BlackDuckVersion != oldVersion && len(checkpointPolicy) > 0 {
+				className, _ := cmd.Flags().GetString("snapshot-class")
+				checkpoint, err = checkpointBlackDuck(blackDuckNamespace, blackDuckName, checkpointPolicy, checkpointTo, className, updateBlackDuckCheckpointTimeout)
+				if err != nil {
+					return fmt.Errorf("failed to take a checkpoint of Black Duck '%s' before the upgrade due to %+v", blackDuckName, err)
+				}
+				currentRevision = checkpoint.revision
+			}
+			checkpointErr := func(err error) error {
+				if checkpoint == nil {
+					return err
+				}
+				return fmt.Errorf("%+v. Restore Black Duck '%s' to the checkpoint taken before the upgrade with '%s'", err, blackDuckName, checkpoint.restoreCommand())
+			}
+
 			for _, v := range secrets {
+				// Save the current secret so that it can be restored by a rollback
+				if err := util.SaveSecretRevision(kubeClient, namespace, blackDuckName, v.Name, currentRevision); err != nil {
+					return checkpointErr(err)
+				}
 				if secret, err := util.GetSecret(kubeClient, namespace, v.Name); err == nil {
 					secret.Data = v.Data
 					secret.StringData = v.StringData
 					if _, err := util.UpdateSecret(kubeClient, namespace, secret); err != nil {
-						return fmt.Errorf("failed to update certificate secret: %+v", err)
+						return checkpointErr(fmt.Errorf("failed to update certificate secret: %+v", err))
 					}
 				} else {
 					if _, err := kubeClient.CoreV1().Secrets(namespace).Create(&v); err != nil {
-						return fmt.Errorf("failed to create certificate secret: %+v", err)
+						return checkpointErr(fmt.Errorf("failed to create certificate secret: %+v", err))
 					}
 				}
 			}
@@ -383,20 +464,26 @@ var updateBlackDuckCmd = &cobra.Command{
 			newVals := util.MergeMaps(instance.Chart.Values, helmValuesMap)
 			err = runBlackDuckFileOwnershipJobs(blackDuckName, blackDuckNamespace, oldVersion, newVals, cmd.Flags())
 			if err != nil {
-				return fmt.Errorf("failed to update File Ownerships in PVs: %+v", err)
+				return checkpointErr(fmt.Errorf("failed to update File Ownerships in PVs: %+v", err))
 			}
 
 			// Deploy resources
 			if err := util.UpdateWithHelm3(blackDuckName, blackDuckNamespace, globals.BlackDuckChartRepository, helmValuesMap, kubeConfigPath); err != nil {
-				return fmt.Errorf("failed to update Black Duck due to %+v", err)
+				return checkpointErr(fmt.Errorf("failed to update Black Duck due to %+v", err))
 			}
 
 			err = blackduck.CRUDServiceOrRoute(restconfig, kubeClient, blackDuckNamespace, args[0], helmValuesMap["exposeui"], helmValuesMap["exposedServiceType"], cmd.Flags().Lookup("expose-ui").Changed)
 			if err != nil {
-				return err
+				return checkpointErr(err)
+			}
+			if checkpoint != nil {
+				log.Infof("Black Duck '%s' can be restored to the checkpoint taken before the upgrade with '%s'", blackDuckName, checkpoint.restoreCommand())
 			}
 
 		} else if isOperatorBased {
+			if diff, _ := cmd.Flags().GetBool("diff"); diff {
+				return fmt.Errorf("--diff is not supported for a Black Duck instance that was deployed by the Synopsys Operator")
+			}
 			if !cmd.Flag("version").Changed {
 				return fmt.Errorf("you must upgrade this Blackduck version with --version 2020.4.0 and above to use this synopsysctl binary")
 			}
@@ -432,11 +519,18 @@ var updateBlackDuckCmd = &cobra.Command{
 			if err != nil {
 				return fmt.Errorf("error getting Black Duck '%s' in namespace '%s' due to %+v", blackDuckName, crdNamespace, err)
 			}
+			if err := snapshotInstance(cmd, util.BlackDuckName, blackDuckName); err != nil {
+				return err
+			}
 			if err := migrate(currBlackDuck, operatorNamespace, crdNamespace, cmd.Flags()); err != nil {
 				return err
 			}
 		}
 
+		if err := waitForInstance(cmd, util.BlackDuckName, blackDuckName); err != nil {
+			return err
+		}
+
 		log.Infof("Black Duck has been successfully Updated in namespace '%s'!", namespace)
 		return nil
 	},
@@ -711,6 +805,24 @@ var updateBlackDuckMasterKeyNativeCmd = &cobra.Command{
 	},
 }
 
+// putBlackDuckMasterKey will recover the master key of the upload cache with the seal key and return the upload cache pod
+func putBlackDuckMasterKey(namespace string, name string, sealKey string, masterKey string) (*corev1.Pod, error) {
+	// Filter the upload cache pod to get the root key using the seal key
+	uploadCachePod, err := util.FilterPodByNamePrefixInNamespace(kubeClient, namespace, util.GetResourceName(name, util.BlackDuckName, "uploadcache"))
+	if err != nil {
+		return nil, fmt.Errorf("unable to filter the upload cache pod in namespace '%s' due to %+v", namespace, err)
+	}
+
+	// Create the exec into Kubernetes pod request
+	req := util.CreateExecContainerRequest(kubeClient, uploadCachePod, "/bin/sh")
+
+	_, err = util.ExecContainer(restconfig, req, []string{fmt.Sprintf(`curl -X PUT --header "X-SEAL-KEY:%s" -H "X-MASTER-KEY:%s" https://localhost:9444/api/internal/recovery --cert /opt/blackduck/hub/blackduck-upload-cache/security/blackduck-upload-cache-server.crt --key /opt/blackduck/hub/blackduck-upload-cache/security/blackduck-upload-cache-server.key --cacert /opt/blackduck/hub/blackduck-upload-cache/security/root.crt`, base64.StdEncoding.EncodeToString([]byte(sealKey)), masterKey)})
+	if err != nil {
+		return nil, fmt.Errorf("unable to exec into upload cache pod in namespace '%s' due to %+v", namespace, err)
+	}
+	return uploadCachePod, nil
+}
+
 // updateMasterKey updates the master key and encoded with new seal key
 func updateMasterKey(namespace string, name string, oldMasterKeyFilePath string, newSealKey string, isNative bool, release *release.Release, cmd *cobra.Command) error {
 
@@ -729,18 +841,9 @@ func updateMasterKey(namespace string, name string, oldMasterKeyFilePath string,
 		return fmt.Errorf("error reading the master key from file '%s' due to %+v", fileName, err)
 	}
 
-	// Filter the upload cache pod to get the root key using the seal key
-	uploadCachePod, err := util.FilterPodByNamePrefixInNamespace(kubeClient, namespace, util.GetResourceName(name, util.BlackDuckName, "uploadcache"))
-	if err != nil {
-		return fmt.Errorf("unable to filter the upload cache pod in namespace '%s' due to %+v", namespace, err)
-	}
-
-	// Create the exec into Kubernetes pod request
-	req := util.CreateExecContainerRequest(kubeClient, uploadCachePod, "/bin/sh")
-
-	_, err = util.ExecContainer(restconfig, req, []string{fmt.Sprintf(`curl -X PUT --header "X-SEAL-KEY:%s" -H "X-MASTER-KEY:%s" https://localhost:9444/api/internal/recovery --cert /opt/blackduck/hub/blackduck-upload-cache/security/blackduck-upload-cache-server.crt --key /opt/blackduck/hub/blackduck-upload-cache/security/blackduck-upload-cache-server.key --cacert /opt/blackduck/hub/blackduck-upload-cache/security/root.crt`, base64.StdEncoding.EncodeToString([]byte(newSealKey)), masterKey)})
+	uploadCachePod, err := putBlackDuckMasterKey(namespace, name, newSealKey, string(masterKey))
 	if err != nil {
-		return fmt.Errorf("unable to exec into upload cache pod in namespace '%s' due to %+v", namespace, err)
+		return err
 	}
 
 	log.Infof("successfully updated the master key in the upload cache container of Black Duck '%s' in namespace '%s'", name, namespace)
@@ -859,6 +962,9 @@ var updateOpsSightCmd = &cobra.Command{
 		return nil
 	},
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := setVersionFromBundle(cmd.Flags()); err != nil {
+			return err
+		}
 		opssightName := args[0]
 
 		// Set flags from the current release in the updateOpsSightCobraHelper
@@ -866,7 +972,12 @@ var updateOpsSightCmd = &cobra.Command{
 		if err != nil {
 			return fmt.Errorf("failed to get previous user defined values: %+v", err)
 		}
-		updateOpsSightCobraHelper.SetArgs(helmRelease.Config)
+		// Set the values from the --values files over the current values, the flags take precedence over them
+		valuesFromFiles, err := mergeValuesFiles(cmd.Flags(), helmRelease.Config)
+		if err != nil {
+			return err
+		}
+		updateOpsSightCobraHelper.SetArgs(valuesFromFiles)
 
 		// Update the Helm Chart Location
 		globals.OpsSightVersion = util.GetValueFromRelease(helmRelease, []string{"imageTag"}).(string)
@@ -884,6 +995,15 @@ var updateOpsSightCmd = &cobra.Command{
 			return err
 		}
 
+		// Show the changes instead of updating OpsSight
+		if diff, _ := cmd.Flags().GetBool("diff"); diff {
+			return printUpdateDiff(opssightName, namespace, globals.OpsSightChartRepository, helmValuesMap)
+		}
+
+		if err := snapshotInstance(cmd, util.OpsSightName, opssightName); err != nil {
+			return err
+		}
+
 		// Update any initial resources that were created...
 
 		// Update OpsSight Resources
@@ -892,6 +1012,10 @@ var updateOpsSightCmd = &cobra.Command{
 			return fmt.Errorf("failed to update OpsSight resources due to %+v", err)
 		}
 
+		if err := waitForInstance(cmd, util.OpsSightName, opssightName); err != nil {
+			return err
+		}
+
 		log.Infof("OpsSight has been successfully updated in namespace '%s'!", namespace)
 
 		return nil
@@ -1206,11 +1330,19 @@ var updatePolarisCmd = &cobra.Command{
 		return nil
 	},
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := setVersionFromBundle(cmd.Flags()); err != nil {
+			return err
+		}
 		helmRelease, err := util.GetWithHelm3(globals.PolarisName, namespace, kubeConfigPath)
 		if err != nil {
 			return fmt.Errorf("failed to get previous user defined values: %+v", err)
 		}
-		updatePolarisCobraHelper.SetArgs(helmRelease.Config)
+		// Set the values from the --values files over the current values, the flags take precedence over them
+		valuesFromFiles, err := mergeValuesFiles(cmd.Flags(), helmRelease.Config)
+		if err != nil {
+			return err
+		}
+		updatePolarisCobraHelper.SetArgs(valuesFromFiles)
 		// Get the flags to set Helm values
 		helmValuesMap, err := updatePolarisCobraHelper.GenerateHelmFlagsFromCobraFlags(cmd.Flags())
 		if err != nil {
@@ -1235,12 +1367,25 @@ var updatePolarisCmd = &cobra.Command{
 			return fmt.Errorf("failed to set the app resources location due to %+v", err)
 		}
 
+		// Show the changes instead of updating Polaris
+		if diff, _ := cmd.Flags().GetBool("diff"); diff {
+			return printUpdateDiff(globals.PolarisName, namespace, globals.PolarisChartRepository, helmValuesMap)
+		}
+
+		if err := snapshotInstance(cmd, globals.PolarisName, globals.PolarisName); err != nil {
+			return err
+		}
+
 		// Deploy Polaris Resources
 		err = util.UpdateWithHelm3(globals.PolarisName, namespace, globals.PolarisChartRepository, helmValuesMap, kubeConfigPath)
 		if err != nil {
 			return fmt.Errorf("failed to update Polaris resources due to %+v", err)
 		}
 
+		if err := waitForInstance(cmd, globals.PolarisName, globals.PolarisName); err != nil {
+			return err
+		}
+
 		log.Infof("Polaris has been successfully Updated in namespace '%s'!", namespace)
 		return nil
 	},
@@ -1266,7 +1411,12 @@ var updatePolarisReportingCmd = &cobra.Command{
 		if err != nil {
 			return fmt.Errorf("failed to get previous user defined values: %+v", err)
 		}
-		updatePolarisReportingCobraHelper.SetArgs(helmRelease.Config)
+		// Set the values from the --values files over the current values, the flags take precedence over them
+		valuesFromFiles, err := mergeValuesFiles(cmd.Flags(), helmRelease.Config)
+		if err != nil {
+			return err
+		}
+		updatePolarisReportingCobraHelper.SetArgs(valuesFromFiles)
 
 		// Get the flags to set Helm values
 		helmValuesMap, err := updatePolarisReportingCobraHelper.GenerateHelmFlagsFromCobraFlags(cmd.Flags())
@@ -1292,12 +1442,25 @@ var updatePolarisReportingCmd = &cobra.Command{
 			return fmt.Errorf("failed to set the app resources location due to %+v", err)
 		}
 
+		// Show the changes instead of updating Polaris-Reporting
+		if diff, _ := cmd.Flags().GetBool("diff"); diff {
+			return printUpdateDiff(globals.PolarisReportingName, namespace, globals.PolarisReportingChartRepository, helmValuesMap)
+		}
+
+		if err := snapshotInstance(cmd, globals.PolarisReportingName, globals.PolarisReportingName); err != nil {
+			return err
+		}
+
 		// Update Polaris-Reporting Resources
 		err = util.UpdateWithHelm3(globals.PolarisReportingName, namespace, globals.PolarisReportingChartRepository, helmValuesMap, kubeConfigPath)
 		if err != nil {
 			return fmt.Errorf("failed to update Polaris-Reporting resources due to %+v", err)
 		}
 
+		if err := waitForInstance(cmd, globals.PolarisReportingName, globals.PolarisReportingName); err != nil {
+			return err
+		}
+
 		log.Infof("Polaris-Reporting has been successfully Updated in namespace '%s'!", namespace)
 		return nil
 	},
@@ -1319,11 +1482,19 @@ var updateBDBACmd = &cobra.Command{
 		return nil
 	},
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := setVersionFromBundle(cmd.Flags()); err != nil {
+			return err
+		}
 		helmRelease, err := util.GetWithHelm3(globals.BDBAName, namespace, kubeConfigPath)
 		if err != nil {
 			return fmt.Errorf("failed to get previous user defined values: %+v", err)
 		}
-		updateBDBACobraHelper.SetArgs(helmRelease.Config)
+		// Set the values from the --values files over the current values, the flags take precedence over them
+		valuesFromFiles, err := mergeValuesFiles(cmd.Flags(), helmRelease.Config)
+		if err != nil {
+			return err
+		}
+		updateBDBACobraHelper.SetArgs(valuesFromFiles)
 
 		// Get the flags to set Helm values
 		helmValuesMap, err := updateBDBACobraHelper.GenerateHelmFlagsFromCobraFlags(cmd.Flags())
@@ -1349,12 +1520,25 @@ var updateBDBACmd = &cobra.Command{
 			return fmt.Errorf("failed to set the app resources location due to %+v", err)
 		}
 
+		// Show the changes instead of updating BDBA
+		if diff, _ := cmd.Flags().GetBool("diff"); diff {
+			return printUpdateDiff(globals.BDBAName, namespace, globals.BDBAChartRepository, helmValuesMap)
+		}
+
+		if err := snapshotInstance(cmd, globals.BDBAName, globals.BDBAName); err != nil {
+			return err
+		}
+
 		// Update Resources
 		err = util.UpdateWithHelm3(globals.BDBAName, namespace, globals.BDBAChartRepository, helmValuesMap, kubeConfigPath)
 		if err != nil {
 			return fmt.Errorf("failed to update BDBA resources due to %+v", err)
 		}
 
+		if err := waitForInstance(cmd, globals.BDBAName, globals.BDBAName); err != nil {
+			return err
+		}
+
 		log.Infof("BDBA has been successfully Updated in namespace '%s'!", namespace)
 		return nil
 	},
@@ -1377,6 +1561,11 @@ func init() {
 	cobra.MarkFlagRequired(updateAlertCmd.PersistentFlags(), "namespace")
 	updateAlertCobraHelper.AddCobraFlagsToCommand(updateAlertCmd, false)
 	addChartLocationPathFlag(updateAlertCmd)
+	addValuesFlag(updateAlertCmd)
+	addDiffFlag(updateAlertCmd)
+	addWaitFlags(updateAlertCmd)
+	addSnapshotFlags(updateAlertCmd)
+	addBundleFlag(updateAlertCmd)
 	updateCmd.AddCommand(updateAlertCmd)
 
 	/* Update Black Duck Comamnds */
@@ -1385,6 +1574,15 @@ func init() {
 	updateBlackDuckCmd.PersistentFlags().StringVarP(&namespace, "namespace", "n", namespace, "Namespace of the instance(s)")
 	cobra.MarkFlagRequired(updateBlackDuckCmd.PersistentFlags(), "namespace")
 	addChartLocationPathFlag(updateBlackDuckCmd)
+	addValuesFlag(updateBlackDuckCmd)
+	addDiffFlag(updateBlackDuckCmd)
+	addWaitFlags(updateBlackDuckCmd)
+	addSnapshotFlags(updateBlackDuckCmd)
+	updateBlackDuckCmd.Flags().StringVar(&updateBlackDuckCheckpoint, "checkpoint", updateBlackDuckCheckpoint, fmt.Sprintf("Stop the instance and take a checkpoint before a version change [%s]: a backup of the database and the keys, volume snapshots of the PVCs, or both (default the checkpoint in the config file, otherwise none)", strings.Join(checkpointPolicies, "|")))
+	updateBlackDuckCmd.Flags().StringVar(&updateBlackDuckCheckpointTo, "checkpoint-to", updateBlackDuckCheckpointTo, "Local directory or PVC in the instance's namespace (pvc:<claim name>) to store the backup of the checkpoint in (default the checkpointTo in the config file)")
+	updateBlackDuckCmd.Flags().BoolVar(&updateBlackDuckNoCheckpoint, "no-checkpoint", updateBlackDuckNoCheckpoint, "Skip the checkpoint that is configured in the config file")
+	updateBlackDuckCmd.Flags().DurationVar(&updateBlackDuckCheckpointTimeout, "checkpoint-timeout", updateBlackDuckCheckpointTimeout, "How long to wait for the instance to stop and for the checkpoint to complete")
+	addBundleFlag(updateBlackDuckCmd)
 	updateBlackDuckCmd.Flags().StringVar(&globals.DefaultBusyBoxImage, "busy-box-image", globals.DefaultBusyBoxImage, "Busy box image override for an air gapped customer (only use in case of updating security contexts)")
 	updateBlackDuckCobraHelper.AddCRSpecFlagsToCommand(updateBlackDuckCmd, false)
 	updateCmd.AddCommand(updateBlackDuckCmd)
@@ -1407,6 +1605,11 @@ func init() {
 	updateOpsSightCmd.PersistentFlags().StringVarP(&namespace, "namespace", "n", namespace, "Namespace of the instance(s)")
 	cobra.MarkFlagRequired(updateOpsSightCmd.PersistentFlags(), "namespace")
 	addChartLocationPathFlag(updateOpsSightCmd)
+	addValuesFlag(updateOpsSightCmd)
+	addDiffFlag(updateOpsSightCmd)
+	addWaitFlags(updateOpsSightCmd)
+	addSnapshotFlags(updateOpsSightCmd)
+	addBundleFlag(updateOpsSightCmd)
 	updateOpsSightCobraHelper.AddCobraFlagsToCommand(updateOpsSightCmd, false)
 	updateCmd.AddCommand(updateOpsSightCmd)
 
@@ -1425,6 +1628,11 @@ func init() {
 	cobra.MarkFlagRequired(updatePolarisCmd.PersistentFlags(), "namespace")
 	updatePolarisCobraHelper.AddCobraFlagsToCommand(updatePolarisCmd, false)
 	addChartLocationPathFlag(updatePolarisCmd)
+	addValuesFlag(updatePolarisCmd)
+	addDiffFlag(updatePolarisCmd)
+	addWaitFlags(updatePolarisCmd)
+	addSnapshotFlags(updatePolarisCmd)
+	addBundleFlag(updatePolarisCmd)
 	updateCmd.AddCommand(updatePolarisCmd)
 
 	// Polaris-Reporting
@@ -1432,6 +1640,10 @@ func init() {
 	cobra.MarkFlagRequired(updatePolarisReportingCmd.PersistentFlags(), "namespace")
 	updatePolarisReportingCobraHelper.AddCobraFlagsToCommand(updatePolarisReportingCmd, false)
 	addChartLocationPathFlag(updatePolarisReportingCmd)
+	addValuesFlag(updatePolarisReportingCmd)
+	addDiffFlag(updatePolarisReportingCmd)
+	addWaitFlags(updatePolarisReportingCmd)
+	addSnapshotFlags(updatePolarisReportingCmd)
 	updateCmd.AddCommand(updatePolarisReportingCmd)
 
 	// BDBA
@@ -1439,5 +1651,10 @@ func init() {
 	cobra.MarkFlagRequired(updateBDBACmd.PersistentFlags(), "namespace")
 	updateBDBACobraHelper.AddCobraFlagsToCommand(updateBDBACmd, false)
 	addChartLocationPathFlag(updateBDBACmd)
+	addValuesFlag(updateBDBACmd)
+	addDiffFlag(updateBDBACmd)
+	addWaitFlags(updateBDBACmd)
+	addSnapshotFlags(updateBDBACmd)
+	addBundleFlag(updateBDBACmd)
 	updateCmd.AddCommand(updateBDBACmd)
 }
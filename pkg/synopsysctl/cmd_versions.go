@@ -0,0 +1,171 @@
+/*
+Copyright (C) 2020 Synopsys, Inc.
+
+Licensed to the Apache Software Foundation (ASF) under one
+or more contributor license agreements. See the NOTICE file
+distributed with this work for additional information
+regarding copyright ownership. The ASF licenses this file
+to you under the Apache License, Version 2.0 (the
+"License"); you may not use this file except in compliance
+with the License. You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing,
+software distributed under the License is distributed on an
+"AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+KIND, either express or implied. See the License for the
+specific language governing permissions and limitations
+under the License.
+*/
+
+package synopsysctl
+
+import (
+	"fmt"
+
+	"github.com/blackducksoftware/synopsysctl/pkg/globals"
+	"github.com/blackducksoftware/synopsysctl/pkg/util"
+	"github.com/spf13/cobra"
+	"helm.sh/helm/v3/pkg/release"
+)
+
+// Versions Command Options and Defaults
+var versionsUpgradable = ""
+
+// versionsProducts are the products in the order they are listed by the versions command
+var versionsProducts = []string{util.BlackDuckName, util.AlertName, util.OpsSightName, globals.BDBAName, globals.PolarisName, globals.PolarisReportingName}
+
+// productVersion is a version of a product that is available in the chart repositories
+type productVersion struct {
+	Product      string `json:"product"`
+	AppVersion   string `json:"appVersion"`
+	ChartVersion string `json:"chartVersion"`
+}
+
+// getProductVersions returns the versions of a product in the chart repositories, newest first
+func getProductVersions(product string) ([]productVersion, error) {
+	chartName, err := getChartName(product)
+	if err != nil {
+		return nil, err
+	}
+	productVersions := []productVersion{}
+	for _, appVersion := range util.GetAppVersions(globals.IndexChartURLs, chartName) {
+		chartVersion, err := util.GetLatestChartVersionForAppVersion(globals.IndexChartURLs, chartName, appVersion)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find the chart for %s version '%s': %+v", product, appVersion, err)
+		}
+		productVersions = append(productVersions, productVersion{Product: product, AppVersion: appVersion, ChartVersion: chartVersion})
+	}
+	return productVersions, nil
+}
+
+// getReleaseNameAndVersionKey returns the release name of an instance of a product and the key of
+// the Helm value with its app version
+func getReleaseNameAndVersionKey(product, name string) (string, []string) {
+	switch product {
+	case util.AlertName:
+		return fmt.Sprintf("%s%s", name, globals.AlertPostSuffix), []string{"alert", "imageTag"}
+	case globals.BDBAName, globals.PolarisName, globals.PolarisReportingName:
+		return name, []string{"version"}
+	}
+	return name, []string{"imageTag"}
+}
+
+// getReleaseAppVersion returns the app version of a release
+func getReleaseAppVersion(helmRelease *release.Release, versionKey []string) string {
+	if versionFromRelease, ok := util.GetValueFromRelease(helmRelease, versionKey).(string); ok && len(versionFromRelease) > 0 {
+		return versionFromRelease
+	}
+	return helmRelease.Chart.Metadata.AppVersion
+}
+
+// getInstalledVersion returns the app version and chart version of an instance of a product
+func getInstalledVersion(product, name, namespace string) (string, string, error) {
+	releaseName, versionKey := getReleaseNameAndVersionKey(product, name)
+	helmRelease, err := util.GetWithHelm3(releaseName, namespace, kubeConfigPath)
+	if err != nil {
+		return "", "", fmt.Errorf("couldn't find instance %s in namespace %s", name, namespace)
+	}
+	return getReleaseAppVersion(helmRelease, versionKey), helmRelease.Chart.Metadata.Version, nil
+}
+
+// filterUpgradableVersions returns the versions that an instance with appVersion and chartVersion can be upgraded to
+func filterUpgradableVersions(productVersions []productVersion, appVersion, chartVersion string) []productVersion {
+	upgradableVersions := []productVersion{}
+	for _, version := range productVersions {
+		switch util.CompareVersions(version.AppVersion, appVersion) {
+		case 1:
+			upgradableVersions = append(upgradableVersions, version)
+		case 0:
+			// A newer chart for the same app version
+			if version.ChartVersion != chartVersion {
+				upgradableVersions = append(upgradableVersions, version)
+			}
+		}
+	}
+	return upgradableVersions
+}
+
+// versionsCmd lists the versions of the products in the chart repositories
+var versionsCmd = &cobra.Command{
+	Use:           "versions [PRODUCT]",
+	Example:       "synopsysctl versions\nsynopsysctl versions blackduck -o json\nsynopsysctl versions blackduck --upgradable <name> -n <namespace>",
+	Short:         "List the versions of the Synopsys products that are available",
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) > 1 {
+			cmd.Help()
+			return fmt.Errorf("this command takes up to 1 argument, but got %+v", args)
+		}
+		if len(versionsUpgradable) > 0 && len(args) != 1 {
+			cmd.Help()
+			return fmt.Errorf("--upgradable requires the product of the instance")
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		products := versionsProducts
+		if len(args) == 1 {
+			products = []string{args[0]}
+		}
+
+		productVersions := []productVersion{}
+		for _, product := range products {
+			versions, err := getProductVersions(product)
+			if err != nil {
+				return err
+			}
+			productVersions = append(productVersions, versions...)
+		}
+
+		if len(versionsUpgradable) > 0 {
+			if len(namespace) == 0 {
+				return fmt.Errorf("--namespace is required with --upgradable")
+			}
+			if err := setGlobalKubeConfigPath(cmd); err != nil {
+				return err
+			}
+			appVersion, chartVersion, err := getInstalledVersion(args[0], versionsUpgradable, namespace)
+			if err != nil {
+				return err
+			}
+			productVersions = filterUpgradableVersions(productVersions, appVersion, chartVersion)
+		}
+
+		table := util.Table{Columns: []util.TableColumn{{Header: "PRODUCT"}, {Header: "APP VERSION"}, {Header: "CHART VERSION"}}}
+		for _, version := range productVersions {
+			table.Rows = append(table.Rows, []string{version.Product, version.AppVersion, version.ChartVersion})
+		}
+		return printOutput(cmd, productVersions, table)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(versionsCmd)
+
+	addOutputFlag(versionsCmd, util.OutputTable)
+	versionsCmd.Flags().StringVar(&versionsUpgradable, "upgradable", versionsUpgradable, "Only list the versions that the instance NAME can be upgraded to")
+	versionsCmd.Flags().StringVarP(&namespace, "namespace", "n", namespace, "Namespace of the instance given to --upgradable")
+}
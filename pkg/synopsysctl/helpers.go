@@ -26,6 +26,7 @@ import (
 	"strings"
 
 	"github.com/blackducksoftware/synopsysctl/pkg/globals"
+	"github.com/blackducksoftware/synopsysctl/pkg/util"
 	"github.com/spf13/cobra"
 )
 
@@ -42,6 +43,45 @@ func addChartLocationPathFlag(cmd *cobra.Command) {
 	// cmd.Flags().MarkHidden("app-resources-path")
 }
 
+func addBundleFlag(cmd *cobra.Command) {
+	var tmp string
+	cmd.Flags().StringVarP(&tmp, "bundle", "", "", "Path to a bundle from 'synopsysctl bundle create' to install the application from")
+}
+
+func addValuesFlag(cmd *cobra.Command) {
+	var tmp []string
+	cmd.Flags().StringSliceVarP(&tmp, "values", "f", tmp, "Path to a YAML file of Helm values, repeat to merge several files in order (flags take precedence over the files)")
+}
+
+func addDiffFlag(cmd *cobra.Command) {
+	var tmp bool
+	cmd.Flags().BoolVar(&tmp, "diff", tmp, "Show the changes to the Helm values and to the resources that the update would make without applying them")
+}
+
+func addWaitFlags(cmd *cobra.Command) {
+	var wait bool
+	cmd.Flags().BoolVar(&wait, "wait", wait, "Wait until the Deployments, pods and PVCs of the instance are ready")
+	timeout := util.DefaultWaitTimeout
+	cmd.Flags().DurationVar(&timeout, "timeout", timeout, "How long to wait for the instance to be ready when --wait is set")
+}
+
+func addSnapshotFlags(cmd *cobra.Command) {
+	var snapshot bool
+	cmd.Flags().BoolVar(&snapshot, "snapshot", snapshot, "Take CSI volume snapshots of the PVCs of the instance before changing it")
+	var snapshotClass string
+	cmd.Flags().StringVar(&snapshotClass, "snapshot-class", snapshotClass, "VolumeSnapshotClass of the volume snapshots taken with --snapshot (default the cluster's default class)")
+}
+
+func addWorkloadsFlag(cmd *cobra.Command) {
+	var tmp bool
+	cmd.Flags().BoolVar(&tmp, "workloads", tmp, "Scale the Deployments and StatefulSets of the instance instead of upgrading its release, so that the chart isn't needed (used by the scheduled starts and stops)")
+}
+
+func addOutputFlag(cmd *cobra.Command, defaultFormat string) {
+	var tmp string
+	cmd.Flags().StringVarP(&tmp, "output", "o", defaultFormat, fmt.Sprintf("Output format [%s]", util.OutputFormatDescription))
+}
+
 func addNativeFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVar(&globals.NativeClusterType, "target", globals.NativeClusterType, "Type of cluster to generate the resources for [KUBERNETES|OPENSHIFT]")
 }
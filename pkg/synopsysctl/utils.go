@@ -38,9 +38,12 @@ import (
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"helm.sh/helm/v3/pkg/release"
 	apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
 	apiextensionsclient "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
@@ -54,6 +57,99 @@ var alertClient *alertclientset.Clientset
 var blackDuckClient *blackduckclientset.Clientset
 var opsSightClient *opssightclientset.Clientset
 
+// setChartRepositories sets the chart repositories from the --chart-repository flags or the chartRepositories
+// in the config file
+func setChartRepositories(cmd *cobra.Command) error {
+	repositories := []*util.ChartRepository{}
+	if len(chartRepositoryURLs) > 0 {
+		for i, url := range chartRepositoryURLs {
+			repositories = append(repositories, &util.ChartRepository{
+				URL:      url,
+				Username: chartRepositoryUsername,
+				Password: chartRepositoryPassword,
+				Token:    chartRepositoryToken,
+				CAFile:   chartRepositoryCAFile,
+				CertFile: chartRepositoryCertFile,
+				KeyFile:  chartRepositoryKeyFile,
+				Priority: len(chartRepositoryURLs) - i, // the repositories are searched in the order of the flags
+			})
+		}
+	} else if viper.IsSet("chartRepositories") {
+		if err := viper.UnmarshalKey("chartRepositories", &repositories); err != nil {
+			return fmt.Errorf("failed to read the chartRepositories in the config file due to %+v", err)
+		}
+		for _, repository := range repositories {
+			// Allow credentials to come from the environment instead of being stored in the config file
+			repository.Username = os.ExpandEnv(repository.Username)
+			repository.Password = os.ExpandEnv(repository.Password)
+			repository.Token = os.ExpandEnv(repository.Token)
+		}
+	}
+	if len(repositories) == 0 {
+		return nil
+	}
+	return util.SetChartRepositories(repositories)
+}
+
+// needsChartIndex returns true if cmd finds the applications' charts in the chart repositories' index. Commands
+// that use a local chart from --app-resources-path or --bundle don't need the index
+func needsChartIndex(cmd *cobra.Command) bool {
+	found := false
+	for _, chartIndexCommand := range chartIndexCommands {
+		if strings.HasPrefix(cmd.CommandPath(), chartIndexCommand) {
+			found = true
+		}
+	}
+	if !found {
+		return false
+	}
+	for _, localChartFlag := range []string{"app-resources-path", "bundle"} {
+		if flag := cmd.Flags().Lookup(localChartFlag); flag != nil && flag.Changed {
+			return false
+		}
+	}
+	return true
+}
+
+// loadChartVersions finds the latest version of each application in the chart repositories' index
+// and uses it as the default version when creating an application
+func loadChartVersions(cmd *cobra.Command) error {
+	if err := globals.LoadChartVersions(); err != nil {
+		return fmt.Errorf("%s (use --app-resources-path or --bundle to install without the chart repository)", err)
+	}
+	if !strings.HasPrefix(cmd.CommandPath(), "synopsysctl create") {
+		return nil
+	}
+	if versionFlag := cmd.Flags().Lookup("version"); versionFlag != nil && !versionFlag.Changed {
+		if defaultVersion := getDefaultVersion(cmd); len(defaultVersion) > 0 {
+			versionFlag.Value.Set(defaultVersion)
+			versionFlag.DefValue = defaultVersion
+		}
+	}
+	return nil
+}
+
+// getDefaultVersion returns the latest version of the application that cmd manages
+func getDefaultVersion(cmd *cobra.Command) string {
+	for c := cmd; c != nil; c = c.Parent() {
+		switch c.Name() {
+		case util.AlertName:
+			return globals.AlertVersion
+		case util.BlackDuckName:
+			return globals.BlackDuckVersion
+		case util.OpsSightName:
+			return globals.OpsSightVersion
+		case globals.BDBAName:
+			return globals.BDBAVersion
+		case globals.PolarisName:
+			return globals.PolarisVersion
+		case globals.PolarisReportingName:
+			return globals.PolarisReportingVersion
+		}
+	}
+	return ""
+}
+
 // setSynopsysctlLogLevel sets the binary's log level to the value stored in logLevelCtl
 func setSynopsysctlLogLevel() error {
 	lvl, err := log.ParseLevel(logLevelCtl)
@@ -87,7 +183,10 @@ func setGlobalKubeConfigPath(cmd *cobra.Command) error {
 func GetKubeClientFromOutsideCluster(kubeconfigpath string, insecureSkipTLSVerify bool) (*rest.Config, error) {
 	// Determine Config Paths
 	if home := homeDir(); len(kubeconfigpath) == 0 && home != "" {
-		kubeconfigpath = filepath.Join(home, ".kube", "config")
+		// Without a kubeconfig file, the in-cluster config is used (e.g. by the jobs of 'synopsysctl schedule')
+		if defaultKubeconfigPath := filepath.Join(home, ".kube", "config"); !isInCluster() || fileExists(defaultKubeconfigPath) {
+			kubeconfigpath = defaultKubeconfigPath
+		}
 	}
 
 	kubeConfig, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
@@ -106,6 +205,17 @@ func GetKubeClientFromOutsideCluster(kubeconfigpath string, insecureSkipTLSVerif
 	return kubeConfig, nil
 }
 
+// isInCluster returns true if synopsysctl is running in a pod of the cluster
+func isInCluster() bool {
+	return len(os.Getenv("KUBERNETES_SERVICE_HOST")) > 0 && len(os.Getenv("KUBERNETES_SERVICE_PORT")) > 0
+}
+
+// fileExists returns true if there is a file at path
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
 // homeDir determines the user's home directory path
 func homeDir() string {
 	if h := os.Getenv("HOME"); h != "" {
@@ -382,11 +492,31 @@ func getInstanceInfo(crdName string, appName string, namespace string, name stri
 
 // SetHelmChartLocation uses --app-resources-path and chartVersion to set the value at *chartVariable
 func SetHelmChartLocation(flags *pflag.FlagSet, chartName, appVersion string, chartVariable *string) error {
+	if bundleFlag := flags.Lookup("bundle"); bundleFlag != nil && bundleFlag.Changed {
+		bundleManifest, chartPath, err := util.ExtractBundleChart(bundleFlag.Value.String())
+		if err != nil {
+			return fmt.Errorf("failed to load the bundle: %+v", err)
+		}
+		if bundleManifest.ChartName != chartName {
+			return fmt.Errorf("the bundle '%s' contains the resources for '%s', not '%s'", bundleFlag.Value.String(), bundleManifest.ChartName, chartName)
+		}
+		if len(appVersion) > 0 && appVersion != bundleManifest.AppVersion && appVersion != bundleManifest.ChartVersion {
+			return fmt.Errorf("the bundle '%s' contains version '%s', but version '%s' was requested", bundleFlag.Value.String(), bundleManifest.AppVersion, appVersion)
+		}
+		*chartVariable = chartPath
+		return nil
+	}
 	chartLocationFlag := flags.Lookup("app-resources-path")
 	if chartLocationFlag.Changed {
 		*chartVariable = chartLocationFlag.Value.String()
 	} else {
 		if len(appVersion) > 0 {
+			// commands that don't load the index before they run load it when they need a chart
+			if len(globals.IndexChartURLs) == 0 {
+				if err := globals.LoadIndexChartURLs(); err != nil {
+					return fmt.Errorf("%s (use --app-resources-path or --bundle to install without the chart repository)", err)
+				}
+			}
 			chartURL, err := util.GetLatestChartURLForAppVersion(globals.IndexChartURLs, chartName, appVersion)
 			if err != nil {
 				return fmt.Errorf("failed to get resources version for '%s': %+v", chartName, err)
@@ -394,9 +524,208 @@ func SetHelmChartLocation(flags *pflag.FlagSet, chartName, appVersion string, ch
 			*chartVariable = chartURL
 		}
 	}
+	if len(*chartVariable) == 0 {
+		return fmt.Errorf("unable to find the resources for '%s' in the chart repositories, use --app-resources-path to provide them", chartName)
+	}
+	return nil
+}
+
+// mergeValuesFiles returns the values from the --values files merged over values
+func mergeValuesFiles(flags *pflag.FlagSet, values map[string]interface{}) (map[string]interface{}, error) {
+	valuesFiles, err := flags.GetStringSlice("values")
+	if err != nil || len(valuesFiles) == 0 {
+		return values, nil
+	}
+	valuesFromFiles, err := util.ReadValuesFiles(valuesFiles)
+	if err != nil {
+		return nil, err
+	}
+	return util.MergeMaps(values, valuesFromFiles), nil
+}
+
+// namedProducts are the products whose instances are given a NAME, the release of the other products is named after them
+var namedProducts = []string{util.AlertName, util.BlackDuckName, util.OpsSightName}
+
+// validateInstanceArgs validates the PRODUCT [NAME] arguments of a command
+func validateInstanceArgs(cmd *cobra.Command, args []string) error {
+	if len(args) < 1 || len(args) > 2 {
+		cmd.Help()
+		return fmt.Errorf("this command takes 1 or 2 arguments, but got %+v", args)
+	}
+	if _, err := getChartName(args[0]); err != nil {
+		return err
+	}
+	if len(args) == 1 && util.IsExistInStringSlice(namedProducts, args[0]) {
+		cmd.Help()
+		return fmt.Errorf("the NAME of the %s instance is required", args[0])
+	}
+	return nil
+}
+
+// getInstanceFromArgs returns the product and the name of the instance from the PRODUCT [NAME] arguments of a command
+func getInstanceFromArgs(args []string) (string, string) {
+	if len(args) == 2 {
+		return args[0], args[1]
+	}
+	return args[0], args[0]
+}
+
+// getInstanceCommand returns the synopsysctl command that runs the action (e.g. stop) on an instance
+func getInstanceCommand(action, product, name string) []string {
+	command := append([]string{"synopsysctl"}, strings.Fields(action)...)
+	command = append(command, product)
+	if util.IsExistInStringSlice(namedProducts, product) {
+		command = append(command, name)
+	}
+	return append(command, "-n", namespace)
+}
+
+// getInstanceLabelSelector returns the label selector of the resources of an instance of a product
+func getInstanceLabelSelector(product, name string) string {
+	switch product {
+	case globals.PolarisName, globals.PolarisReportingName, globals.BDBAName:
+		// There can only be one instance of these products in a namespace, so all of its resources belong to it
+		return ""
+	}
+	return fmt.Sprintf("app=%s,name=%s", product, name)
+}
+
+// getInstancePVCLabelSelector returns the label selector of the PVCs of an instance of a product
+func getInstancePVCLabelSelector(product, name string) string {
+	switch product {
+	case globals.PolarisName, globals.PolarisReportingName:
+		return "app.kubernetes.io/name=eventstore"
+	}
+	if labelSelector := getInstanceLabelSelector(product, name); len(labelSelector) > 0 {
+		return fmt.Sprintf("%s,component=pvc", labelSelector)
+	}
+	return "component=pvc"
+}
+
+// snapshotInstance takes volume snapshots of the PVCs of an instance if --snapshot is set, and records them in the
+// current revision of the instance's release
+func snapshotInstance(cmd *cobra.Command, product, name string) error {
+	if snapshot, _ := cmd.Flags().GetBool("snapshot"); !snapshot {
+		return nil
+	}
+	className, _ := cmd.Flags().GetString("snapshot-class")
+	set, err := takeInstanceSnapshots(product, name, className)
+	if err != nil {
+		return err
+	}
+	log.Infof("restore the volume snapshots with '%s --set %s'", strings.Join(getInstanceCommand("snapshot restore", product, name), " "), set)
+	return nil
+}
+
+// takeInstanceSnapshots takes volume snapshots of the PVCs of an instance, records them in the current revision of
+// the instance's release, and returns the set of the snapshots
+func takeInstanceSnapshots(product, name, className string) (string, error) {
+	dynamicClient, err := dynamic.NewForConfig(restconfig)
+	if err != nil {
+		return "", fmt.Errorf("error creating the dynamic client due to %+v", err)
+	}
+
+	log.Infof("taking volume snapshots of the PVCs of %s '%s' in namespace '%s'...", product, name, namespace)
+	set, snapshotNames, err := util.CreateVolumeSnapshots(kubeClient, dynamicClient, namespace, product, name, getInstancePVCLabelSelector(product, name), className, util.DefaultWaitTimeout)
+	if err != nil {
+		return "", fmt.Errorf("failed to take volume snapshots of %s '%s' due to %+v", product, name, err)
+	}
+
+	// an instance that is migrated from the Synopsys Operator doesn't have a release yet
+	releaseName, _ := getReleaseNameAndVersionKey(product, name)
+	if instance, err := util.GetWithHelm3(releaseName, namespace, kubeConfigPath); err == nil {
+		if err := util.AnnotateReleaseSnapshots(kubeClient, namespace, releaseName, instance.Version, snapshotNames); err != nil {
+			return "", err
+		}
+	}
+	log.Infof("successfully took %d volume snapshot(s) in set '%s'", len(snapshotNames), set)
+	return set, nil
+}
+
+// waitForInstance waits until the instance is ready if --wait is set
+func waitForInstance(cmd *cobra.Command, product, name string) error {
+	if wait, _ := cmd.Flags().GetBool("wait"); !wait {
+		return nil
+	}
+	timeout, err := cmd.Flags().GetDuration("timeout")
+	if err != nil {
+		return err
+	}
+	log.Infof("waiting up to %s for %s '%s' in namespace '%s' to be ready...", timeout, product, name, namespace)
+	if err := util.WaitForInstanceReady(kubeClient, namespace, getInstanceLabelSelector(product, name), timeout); err != nil {
+		return fmt.Errorf("%s '%s' isn't ready: %s", product, name, err)
+	}
 	return nil
 }
 
+// setBlackDuckStatus stops or starts a Black Duck instance by updating its status value to Stopped or Running
+func setBlackDuckStatus(flags *pflag.FlagSet, instance *release.Release, status string) error {
+	// Update the Helm Chart Location
+	blackDuckVersionFromRelease := util.GetValueFromRelease(instance, []string{"imageTag"}).(string)
+	err := SetHelmChartLocation(flags, globals.BlackDuckChartName, blackDuckVersionFromRelease, &globals.BlackDuckChartRepository)
+	if err != nil {
+		return fmt.Errorf("failed to set the app resources location due to %+v", err)
+	}
+
+	helmValuesMap := instance.Config
+	util.SetHelmValueInMap(helmValuesMap, []string{"status"}, status)
+
+	err = util.UpdateWithHelm3(instance.Name, instance.Namespace, globals.BlackDuckChartRepository, helmValuesMap, kubeConfigPath)
+	if err != nil {
+		return fmt.Errorf("failed to create Blackduck resources: %+v", err)
+	}
+	return nil
+}
+
+// printOutput prints obj, or the tables in the table and wide formats, in the format of the --output flag
+func printOutput(cmd *cobra.Command, obj interface{}, tables ...util.Table) error {
+	format, err := cmd.Flags().GetString("output")
+	if err != nil {
+		return err
+	}
+	return util.PrintOutput(os.Stdout, format, obj, tables...)
+}
+
+// isTableOutput returns true if the --output flag is set to the table or wide format
+func isTableOutput(cmd *cobra.Command) bool {
+	format, _ := cmd.Flags().GetString("output")
+	return format == util.OutputTable || format == util.OutputWide
+}
+
+// printUpdateDiff prints the changes that updating the release with helmValuesMap would make, the diff is
+// only colorized when it is printed to a terminal
+func printUpdateDiff(releaseName, namespace, chartURL string, helmValuesMap map[string]interface{}) error {
+	color := false
+	if stat, err := os.Stdout.Stat(); err == nil && stat.Mode()&os.ModeCharDevice != 0 {
+		color = true
+	}
+	diff, err := util.DiffWithHelm3(releaseName, namespace, chartURL, helmValuesMap, kubeConfigPath, color)
+	if err != nil {
+		return fmt.Errorf("failed to get the changes of the update due to %s", err)
+	}
+	fmt.Print(diff)
+	return nil
+}
+
+// setVersionFromBundle sets the version flag to the version in the bundle if a bundle
+// is used and a version wasn't provided
+func setVersionFromBundle(flags *pflag.FlagSet) error {
+	bundleFlag := flags.Lookup("bundle")
+	versionFlag := flags.Lookup("version")
+	if bundleFlag == nil || !bundleFlag.Changed || versionFlag == nil || versionFlag.Changed {
+		return nil
+	}
+	bundleManifest, err := util.ReadBundleManifest(bundleFlag.Value.String())
+	if err != nil {
+		return fmt.Errorf("failed to load the bundle: %+v", err)
+	}
+	version := bundleManifest.AppVersion
+	if len(version) == 0 {
+		version = bundleManifest.ChartVersion
+	}
+	return flags.Set("version", version)
+}
+
 func cleanAlertHelmError(errString, releaseName, alertName string) string {
 	helmName := fmt.Sprintf("release '%s'", releaseName)
 	instanceName := fmt.Sprintf("instance '%s'", alertName)
@@ -0,0 +1,263 @@
+/*
+Copyright (C) 2020 Synopsys, Inc.
+
+Licensed to the Apache Software Foundation (ASF) under one
+or more contributor license agreements. See the NOTICE file
+distributed with this work for additional information
+regarding copyright ownership. The ASF licenses this file
+to you under the Apache License, Version 2.0 (the
+"License"); you may not use this file except in compliance
+with the License. You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing,
+software distributed under the License is distributed on an
+"AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+KIND, either express or implied. See the License for the
+specific language governing permissions and limitations
+under the License.
+*/
+
+package util
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
+)
+
+const (
+	// bundleManifestFileName is the file in a bundle that describes its contents
+	bundleManifestFileName = "bundle.yaml"
+	// bundleValuesFileName is the file in a bundle with the default values of the chart
+	bundleValuesFileName = "values.yaml"
+	// bundleImagesFileName is the file in a bundle that lists the images used by the chart
+	bundleImagesFileName = "images.txt"
+	// bundleChartsDir is the directory in a bundle that holds the chart
+	bundleChartsDir = "charts"
+	// bundleSizesDir is the directory in a bundle that holds the size files of the chart
+	bundleSizesDir = "sizes"
+)
+
+// BundleManifest describes the contents of an installation bundle
+type BundleManifest struct {
+	Product      string   `json:"product"`
+	ChartName    string   `json:"chartName"`
+	ChartVersion string   `json:"chartVersion"`
+	AppVersion   string   `json:"appVersion"`
+	ChartFile    string   `json:"chartFile"`
+	SizeFiles    []string `json:"sizeFiles,omitempty"`
+	Images       []string `json:"images"`
+}
+
+// CreateBundle packages the chart at chartURL, its size files, the images referenced by its
+// manifests, including the ones of the components that are off by default, and its default values
+// into a single archive at bundlePath so a product can be installed without access to the chart repository
+func CreateBundle(bundlePath, product, chartURL string) (*BundleManifest, error) {
+	chartPath, err := getChartArchive(chartURL)
+	if err != nil {
+		return nil, err
+	}
+	chartBytes, err := ioutil.ReadFile(chartPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the chart '%s' due to %+v", chartPath, err)
+	}
+	chart, err := loader.Load(chartPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the chart '%s' due to %s", chartPath, err)
+	}
+
+	// Render the manifests with the default values and with the optional components on to find the images
+	actionConfig, err := CreateHelmActionConfiguration("", "", "default")
+	if err != nil {
+		return nil, err
+	}
+	images, err := getAllChartImages(product, "default", chart, actionConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render the manifests of '%s' due to %s", chartURL, err)
+	}
+
+	valuesBytes, err := yaml.Marshal(chart.Values)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert the default values of '%s' to yaml due to %+v", chartURL, err)
+	}
+
+	manifest := &BundleManifest{
+		Product:      product,
+		ChartName:    chart.Metadata.Name,
+		ChartVersion: chart.Metadata.Version,
+		AppVersion:   chart.Metadata.AppVersion,
+		ChartFile:    filepath.Base(chartPath),
+		SizeFiles:    []string{},
+		Images:       images,
+	}
+	sizeFiles := getSizeFilesFromChart(chart)
+	for _, sizeFile := range sizeFiles {
+		manifest.SizeFiles = append(manifest.SizeFiles, sizeFile.Name)
+	}
+	manifestBytes, err := yaml.Marshal(manifest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert the bundle manifest to yaml due to %+v", err)
+	}
+
+	bundleFile, err := os.Create(bundlePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the bundle '%s' due to %+v", bundlePath, err)
+	}
+	defer bundleFile.Close()
+	gzipWriter := gzip.NewWriter(bundleFile)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	files := []struct {
+		name string
+		data []byte
+	}{
+		{bundleManifestFileName, manifestBytes},
+		{path.Join(bundleChartsDir, manifest.ChartFile), chartBytes},
+		{bundleValuesFileName, valuesBytes},
+		{bundleImagesFileName, []byte(strings.Join(manifest.Images, "\n") + "\n")},
+	}
+	for _, sizeFile := range sizeFiles {
+		files = append(files, struct {
+			name string
+			data []byte
+		}{path.Join(bundleSizesDir, sizeFile.Name), sizeFile.Data})
+	}
+	for _, file := range files {
+		header := &tar.Header{Name: file.name, Mode: 0644, Size: int64(len(file.data))}
+		if err := tarWriter.WriteHeader(header); err != nil {
+			return nil, fmt.Errorf("failed to add '%s' to the bundle due to %+v", file.name, err)
+		}
+		if _, err := tarWriter.Write(file.data); err != nil {
+			return nil, fmt.Errorf("failed to add '%s' to the bundle due to %+v", file.name, err)
+		}
+	}
+	if err := tarWriter.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write the bundle '%s' due to %+v", bundlePath, err)
+	}
+	if err := gzipWriter.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write the bundle '%s' due to %+v", bundlePath, err)
+	}
+	return manifest, nil
+}
+
+// ReadBundleManifest returns the manifest of the bundle at bundlePath
+func ReadBundleManifest(bundlePath string) (*BundleManifest, error) {
+	bundleFiles, err := readBundleFiles(bundlePath, bundleManifestFileName)
+	if err != nil {
+		return nil, err
+	}
+	return parseBundleManifest(bundlePath, bundleFiles)
+}
+
+// ExtractBundleChart adds the chart in the bundle at bundlePath to the chart cache and returns
+// the bundle's manifest and the location of the chart
+func ExtractBundleChart(bundlePath string) (*BundleManifest, string, error) {
+	bundleFiles, err := readBundleFiles(bundlePath, "")
+	if err != nil {
+		return nil, "", err
+	}
+	manifest, err := parseBundleManifest(bundlePath, bundleFiles)
+	if err != nil {
+		return nil, "", err
+	}
+	chartBytes, ok := bundleFiles[path.Join(bundleChartsDir, manifest.ChartFile)]
+	if !ok {
+		return nil, "", fmt.Errorf("the bundle '%s' is missing the chart '%s'", bundlePath, manifest.ChartFile)
+	}
+
+	if err := os.MkdirAll(chartCache.Dir, 0755); err != nil {
+		return nil, "", fmt.Errorf("failed to create the chart cache directory '%s' due to %+v", chartCache.Dir, err)
+	}
+	chartPath := chartCache.ChartFilePath(manifest.ChartFile)
+	if err := ioutil.WriteFile(chartPath, chartBytes, 0644); err != nil {
+		return nil, "", fmt.Errorf("failed to write '%s' to the chart cache due to %+v", chartPath, err)
+	}
+	return manifest, chartPath, nil
+}
+
+// readBundleFiles returns the contents of the files in the bundle at bundlePath, only
+// reading fileName if it is set
+func readBundleFiles(bundlePath, fileName string) (map[string][]byte, error) {
+	bundleFile, err := os.Open(bundlePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the bundle '%s' due to %+v", bundlePath, err)
+	}
+	defer bundleFile.Close()
+	gzipReader, err := gzip.NewReader(bundleFile)
+	if err != nil {
+		return nil, fmt.Errorf("'%s' is not a valid bundle: %+v", bundlePath, err)
+	}
+	defer gzipReader.Close()
+
+	bundleFiles := map[string][]byte{}
+	tarReader := tar.NewReader(gzipReader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the bundle '%s' due to %+v", bundlePath, err)
+		}
+		if header.Typeflag != tar.TypeReg || (len(fileName) > 0 && header.Name != fileName) {
+			continue
+		}
+		data, err := ioutil.ReadAll(tarReader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read '%s' from the bundle '%s' due to %+v", header.Name, bundlePath, err)
+		}
+		bundleFiles[header.Name] = data
+	}
+	return bundleFiles, nil
+}
+
+// parseBundleManifest converts the manifest in bundleFiles into a BundleManifest
+func parseBundleManifest(bundlePath string, bundleFiles map[string][]byte) (*BundleManifest, error) {
+	manifestBytes, ok := bundleFiles[bundleManifestFileName]
+	if !ok {
+		return nil, fmt.Errorf("'%s' is not a valid bundle: missing '%s'", bundlePath, bundleManifestFileName)
+	}
+	manifest := &BundleManifest{}
+	if err := yaml.Unmarshal(manifestBytes, manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse the manifest of the bundle '%s' due to %+v", bundlePath, err)
+	}
+	return manifest, nil
+}
+
+// getChartArchive returns the location of the packaged chart at chartURL, adding it to the chart cache
+// if it isn't on the local machine
+func getChartArchive(chartURL string) (string, error) {
+	if info, err := os.Stat(chartURL); err == nil && !info.IsDir() {
+		return chartURL, nil
+	}
+	if chartPath, ok := chartCache.Lookup(chartURL); ok {
+		return chartPath, nil
+	}
+	return chartCache.Pull(chartURL)
+}
+
+// getSizeFilesFromChart returns the yaml files at the top level of a chart (small.yaml, medium.yaml, ...)
+func getSizeFilesFromChart(helmChart *chart.Chart) []*chart.File {
+	sizeFiles := []*chart.File{}
+	for _, file := range helmChart.Files {
+		if strings.Contains(file.Name, "/") || file.Name == bundleValuesFileName {
+			continue
+		}
+		if ext := filepath.Ext(file.Name); ext == ".yaml" || ext == ".yml" {
+			sizeFiles = append(sizeFiles, file)
+		}
+	}
+	return sizeFiles
+}
@@ -0,0 +1,100 @@
+/*
+Copyright (C) 2020 Synopsys, Inc.
+
+Licensed to the Apache Software Foundation (ASF) under one
+or more contributor license agreements. See the NOTICE file
+distributed with this work for additional information
+regarding copyright ownership. The ASF licenses this file
+to you under the Apache License, Version 2.0 (the
+"License"); you may not use this file except in compliance
+with the License. You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing,
+software distributed under the License is distributed on an
+"AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+KIND, either express or implied. See the License for the
+specific language governing permissions and limitations
+under the License.
+*/
+
+package util
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+)
+
+func TestCreateAndExtractBundle(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bundle")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	testChart := &chart.Chart{
+		Metadata: &chart.Metadata{
+			APIVersion: chart.APIVersionV2,
+			Name:       "blackduck",
+			Version:    "2020.4.0",
+			AppVersion: "2020.4.0",
+		},
+		Raw: []*chart.File{
+			{Name: "values.yaml", Data: []byte("registry: docker.io/blackducksoftware\nenableBinaryScanner: false\n")},
+		},
+		Templates: []*chart.File{
+			{
+				Name: "templates/webserver.yaml",
+				Data: []byte("apiVersion: v1\nkind: Pod\nmetadata:\n  name: webserver\nspec:\n  containers:\n  - name: webserver\n    image: {{ .Values.registry }}/blackduck-nginx:1.0.26\n"),
+			},
+			{
+				Name: "templates/binaryscanner.yaml",
+				Data: []byte("{{ if .Values.enableBinaryScanner }}apiVersion: v1\nkind: Pod\nmetadata:\n  name: binaryscanner\nspec:\n  containers:\n  - name: binaryscanner\n    image: {{ .Values.registry }}/appcheck-worker:2020.03\n{{ end }}"),
+			},
+		},
+		Files: []*chart.File{
+			{Name: "small.yaml", Data: []byte("webserver:\n  resources:\n    limits:\n      memory: 512Mi\n")},
+			{Name: "README.md", Data: []byte("Black Duck")},
+		},
+	}
+	chartPath, err := chartutil.Save(testChart, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bundlePath := filepath.Join(dir, "blackduck-bundle.tgz")
+	manifest, err := CreateBundle(bundlePath, "blackduck", chartPath)
+	assert.NoError(t, err)
+	assert.Equal(t, &BundleManifest{
+		Product:      "blackduck",
+		ChartName:    "blackduck",
+		ChartVersion: "2020.4.0",
+		AppVersion:   "2020.4.0",
+		ChartFile:    "blackduck-2020.4.0.tgz",
+		SizeFiles:    []string{"small.yaml"},
+		Images:       []string{"docker.io/blackducksoftware/appcheck-worker:2020.03", "docker.io/blackducksoftware/blackduck-nginx:1.0.26"},
+	}, manifest)
+
+	readManifest, err := ReadBundleManifest(bundlePath)
+	assert.NoError(t, err)
+	assert.Equal(t, manifest, readManifest)
+
+	// Extract the chart into an empty chart cache
+	defer SetChartCacheDir(chartCache.Dir)
+	SetChartCacheDir(filepath.Join(dir, "cache"))
+	_, extractedChartPath, err := ExtractBundleChart(bundlePath)
+	assert.NoError(t, err)
+	cachedChartPath, ok := chartCache.Lookup("blackduck-2020.4.0.tgz")
+	assert.True(t, ok)
+	assert.Equal(t, cachedChartPath, extractedChartPath)
+
+	_, err = ReadBundleManifest(chartPath)
+	assert.Error(t, err, "a chart is not a bundle")
+}
@@ -0,0 +1,233 @@
+/*
+Copyright (C) 2020 Synopsys, Inc.
+
+Licensed to the Apache Software Foundation (ASF) under one
+or more contributor license agreements. See the NOTICE file
+distributed with this work for additional information
+regarding copyright ownership. The ASF licenses this file
+to you under the Apache License, Version 2.0 (the
+"License"); you may not use this file except in compliance
+with the License. You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing,
+software distributed under the License is distributed on an
+"AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+KIND, either express or implied. See the License for the
+specific language governing permissions and limitations
+under the License.
+*/
+
+package util
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/getter"
+	"helm.sh/helm/v3/pkg/repo"
+)
+
+// chartCacheIndexFileName is the name of the copy of the chart repository's index stored in the cache
+const chartCacheIndexFileName = "index.yaml"
+
+// chartCacheIndexSourceExtension is appended to the cached index's path to get the file with the chart repositories it came from
+const chartCacheIndexSourceExtension = ".source"
+
+// ChartCache is a directory on disk that stores the chart repository's index and
+// packaged charts (<chart-name>-<chart-version>.tgz) so charts can be resolved without network access
+type ChartCache struct {
+	Dir string
+}
+
+var chartCache = NewChartCache(DefaultChartCacheDir())
+
+// NewChartCache creates a ChartCache that stores charts in dir
+func NewChartCache(dir string) *ChartCache {
+	return &ChartCache{Dir: dir}
+}
+
+// DefaultChartCacheDir returns the directory used for the chart cache if one isn't configured
+func DefaultChartCacheDir() string {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		cacheDir = os.TempDir()
+	}
+	return filepath.Join(cacheDir, "synopsysctl", "charts")
+}
+
+// GetChartCache returns the ChartCache used to resolve charts
+func GetChartCache() *ChartCache {
+	return chartCache
+}
+
+// SetChartCacheDir changes the directory of the ChartCache used to resolve charts
+func SetChartCacheDir(dir string) {
+	chartCache = NewChartCache(dir)
+}
+
+// IndexFilePath returns the location of the cached chart repository index
+func (c *ChartCache) IndexFilePath() string {
+	return filepath.Join(c.Dir, chartCacheIndexFileName)
+}
+
+// ChartFilePath returns the location in the cache for the chart at chartURL
+func (c *ChartCache) ChartFilePath(chartURL string) string {
+	return filepath.Join(c.Dir, path.Base(chartURL))
+}
+
+// SaveIndexFile stores a copy of the index of the chart repositories at source in the cache
+func (c *ChartCache) SaveIndexFile(indexFile *repo.IndexFile, source string) error {
+	if err := os.MkdirAll(c.Dir, 0755); err != nil {
+		return fmt.Errorf("failed to create the chart cache directory '%s' due to %+v", c.Dir, err)
+	}
+	if err := indexFile.WriteFile(c.IndexFilePath(), 0644); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(c.IndexFilePath()+chartCacheIndexSourceExtension, []byte(source), 0644)
+}
+
+// LoadFreshIndexFile returns the chart repository index stored in the cache if it was
+// downloaded from source within ttl
+func (c *ChartCache) LoadFreshIndexFile(source string, ttl time.Duration) (*repo.IndexFile, bool) {
+	if ttl <= 0 {
+		return nil, false
+	}
+	info, err := os.Stat(c.IndexFilePath())
+	if err != nil || time.Since(info.ModTime()) > ttl {
+		return nil, false
+	}
+	if cachedSource, err := ioutil.ReadFile(c.IndexFilePath() + chartCacheIndexSourceExtension); err != nil || string(cachedSource) != source {
+		return nil, false
+	}
+	indexFile, err := repo.LoadIndexFile(c.IndexFilePath())
+	if err != nil {
+		return nil, false
+	}
+	return indexFile, true
+}
+
+// LoadIndexFile returns the chart repository index stored in the cache merged with
+// an entry for every chart in the cache that the stored index doesn't reference
+func (c *ChartCache) LoadIndexFile() (*repo.IndexFile, error) {
+	indexFile, err := repo.LoadIndexFile(c.IndexFilePath())
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to load the cached chart index '%s' due to %+v", c.IndexFilePath(), err)
+		}
+		indexFile = repo.NewIndexFile()
+	}
+	cachedCharts, err := repo.IndexDirectory(c.Dir, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to index the chart cache '%s' due to %+v", c.Dir, err)
+	}
+	indexFile.Merge(cachedCharts)
+	if len(indexFile.Entries) == 0 {
+		return nil, fmt.Errorf("the chart cache '%s' is empty", c.Dir)
+	}
+	indexFile.SortEntries()
+	return indexFile, nil
+}
+
+// Lookup returns the path to the cached copy of the chart at chartURL if it exists
+func (c *ChartCache) Lookup(chartURL string) (string, bool) {
+	if len(chartURL) == 0 {
+		return "", false
+	}
+	chartPath := c.ChartFilePath(chartURL)
+	if _, err := os.Stat(chartPath); err != nil {
+		return "", false
+	}
+	return chartPath, true
+}
+
+// Pull downloads the chart at chartURL into the cache and returns the path to the cached chart
+func (c *ChartCache) Pull(chartURL string) (string, error) {
+	u, err := url.Parse(chartURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid chart URL '%s': %+v", chartURL, err)
+	}
+	g, err := getGettersForURL(chartURL, DefaultChartDownloadTimeout).ByScheme(u.Scheme)
+	if err != nil {
+		return "", fmt.Errorf("unable to download '%s': %s", chartURL, err)
+	}
+	data, err := g.Get(chartURL, getter.WithURL(chartURL))
+	if err != nil {
+		return "", fmt.Errorf("failed to download '%s' due to %s", chartURL, err)
+	}
+
+	// Verify the download is a chart before it's stored in the cache
+	chartBytes := data.Bytes()
+	if _, err := loader.LoadArchive(bytes.NewReader(chartBytes)); err != nil {
+		return "", fmt.Errorf("'%s' is not a valid chart: %s", chartURL, err)
+	}
+
+	if err := os.MkdirAll(c.Dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create the chart cache directory '%s' due to %+v", c.Dir, err)
+	}
+	chartPath := c.ChartFilePath(chartURL)
+	if err := ioutil.WriteFile(chartPath, chartBytes, 0644); err != nil {
+		return "", fmt.Errorf("failed to write '%s' to the chart cache due to %+v", chartPath, err)
+	}
+	log.Debugf("cached chart '%s' at '%s'", chartURL, chartPath)
+	return chartPath, nil
+}
+
+// List returns the charts stored in the cache sorted by name and newest version first
+func (c *ChartCache) List() ([]*repo.ChartVersion, error) {
+	cachedCharts, err := repo.IndexDirectory(c.Dir, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to index the chart cache '%s' due to %+v", c.Dir, err)
+	}
+	cachedCharts.SortEntries()
+
+	names := []string{}
+	for name := range cachedCharts.Entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	chartVersions := []*repo.ChartVersion{}
+	for _, name := range names {
+		chartVersions = append(chartVersions, cachedCharts.Entries[name]...)
+	}
+	return chartVersions, nil
+}
+
+// Prune removes cached charts, keeping the newest 'keep' versions of each chart, and
+// returns the files that were removed
+func (c *ChartCache) Prune(keep int) ([]string, error) {
+	chartVersions, err := c.List()
+	if err != nil {
+		return nil, err
+	}
+	removed := []string{}
+	versionsSeen := map[string]int{}
+	for _, chartVersion := range chartVersions {
+		versionsSeen[chartVersion.Name]++
+		if versionsSeen[chartVersion.Name] <= keep {
+			continue
+		}
+		for _, chartFile := range chartVersion.URLs {
+			chartPath := c.ChartFilePath(chartFile)
+			if err := os.Remove(chartPath); err != nil && !os.IsNotExist(err) {
+				return removed, fmt.Errorf("failed to remove '%s' from the chart cache due to %+v", chartPath, err)
+			}
+			removed = append(removed, chartPath)
+			if err := os.Remove(chartPath + chartProvenanceFileExtension); err == nil {
+				removed = append(removed, chartPath+chartProvenanceFileExtension)
+			}
+		}
+	}
+	return removed, nil
+}
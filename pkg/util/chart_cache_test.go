@@ -0,0 +1,168 @@
+/*
+Copyright (C) 2020 Synopsys, Inc.
+
+Licensed to the Apache Software Foundation (ASF) under one
+or more contributor license agreements. See the NOTICE file
+distributed with this work for additional information
+regarding copyright ownership. The ASF licenses this file
+to you under the Apache License, Version 2.0 (the
+"License"); you may not use this file except in compliance
+with the License. You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing,
+software distributed under the License is distributed on an
+"AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+KIND, either express or implied. See the License for the
+specific language governing permissions and limitations
+under the License.
+*/
+
+package util
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+)
+
+// saveTestChart packages an empty chart into dir
+func saveTestChart(t *testing.T, dir, name, version, appVersion string) {
+	testChart := &chart.Chart{
+		Metadata: &chart.Metadata{
+			APIVersion: chart.APIVersionV2,
+			Name:       name,
+			Version:    version,
+			AppVersion: appVersion,
+		},
+	}
+	if _, err := chartutil.Save(testChart, dir); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestChartCacheLookup(t *testing.T) {
+	dir, err := ioutil.TempDir("", "chart-cache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	saveTestChart(t, dir, "blackduck", "2020.4.0", "2020.4.0")
+
+	type test struct {
+		testDesc     string
+		chartURL     string
+		expectedPath string
+		expectedOk   bool
+	}
+	tests := []test{
+		{
+			testDesc:     "remote chart in the cache",
+			chartURL:     "https://sig-repo.synopsys.com/sig-cloudnative/blackduck-2020.4.0.tgz",
+			expectedPath: filepath.Join(dir, "blackduck-2020.4.0.tgz"),
+			expectedOk:   true,
+		},
+		{
+			testDesc:     "chart name in the cache",
+			chartURL:     "blackduck-2020.4.0.tgz",
+			expectedPath: filepath.Join(dir, "blackduck-2020.4.0.tgz"),
+			expectedOk:   true,
+		},
+		{
+			testDesc:     "chart not in the cache",
+			chartURL:     "https://sig-repo.synopsys.com/sig-cloudnative/blackduck-2020.6.0.tgz",
+			expectedPath: "",
+			expectedOk:   false,
+		},
+		{
+			testDesc:     "empty chart URL",
+			chartURL:     "",
+			expectedPath: "",
+			expectedOk:   false,
+		},
+	}
+
+	chartCache := NewChartCache(dir)
+	for _, test := range tests {
+		chartPath, ok := chartCache.Lookup(test.chartURL)
+		assert.Equal(t, test.expectedOk, ok, test.testDesc)
+		assert.Equal(t, test.expectedPath, chartPath, test.testDesc)
+	}
+}
+
+func TestChartCacheLoadIndexFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "chart-cache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	chartCache := NewChartCache(dir)
+	_, err = chartCache.LoadIndexFile()
+	assert.Error(t, err, "an empty chart cache has no index")
+
+	saveTestChart(t, dir, "synopsys-alert", "5.3.0", "5.3.0")
+	indexFile, err := chartCache.LoadIndexFile()
+	assert.NoError(t, err)
+	assert.True(t, indexFile.Has("synopsys-alert", "5.3.0"))
+}
+
+func TestChartCachePrune(t *testing.T) {
+	type test struct {
+		testDesc        string
+		keep            int
+		expectedRemoved []string
+		expectedKept    []string
+	}
+	tests := []test{
+		{
+			testDesc:        "keep the latest version of each chart",
+			keep:            1,
+			expectedRemoved: []string{"blackduck-2020.2.0.tgz", "blackduck-2020.2.1.tgz"},
+			expectedKept:    []string{"blackduck-2020.4.0.tgz", "synopsys-alert-5.3.0.tgz"},
+		},
+		{
+			testDesc:        "keep more versions than are cached",
+			keep:            5,
+			expectedRemoved: []string{},
+			expectedKept:    []string{"blackduck-2020.2.0.tgz", "blackduck-2020.2.1.tgz", "blackduck-2020.4.0.tgz", "synopsys-alert-5.3.0.tgz"},
+		},
+		{
+			testDesc:        "remove every chart",
+			keep:            0,
+			expectedRemoved: []string{"blackduck-2020.4.0.tgz", "blackduck-2020.2.1.tgz", "blackduck-2020.2.0.tgz", "synopsys-alert-5.3.0.tgz"},
+			expectedKept:    []string{},
+		},
+	}
+
+	for _, test := range tests {
+		dir, err := ioutil.TempDir("", "chart-cache")
+		if err != nil {
+			t.Fatal(err)
+		}
+		saveTestChart(t, dir, "blackduck", "2020.2.0", "2020.2.0")
+		saveTestChart(t, dir, "blackduck", "2020.2.1", "2020.2.1")
+		saveTestChart(t, dir, "blackduck", "2020.4.0", "2020.4.0")
+		saveTestChart(t, dir, "synopsys-alert", "5.3.0", "5.3.0")
+
+		chartCache := NewChartCache(dir)
+		removed, err := chartCache.Prune(test.keep)
+		assert.NoError(t, err, test.testDesc)
+		removedNames := []string{}
+		for _, chartPath := range removed {
+			removedNames = append(removedNames, filepath.Base(chartPath))
+		}
+		assert.ElementsMatch(t, test.expectedRemoved, removedNames, test.testDesc)
+		for _, chartName := range test.expectedKept {
+			_, ok := chartCache.Lookup(chartName)
+			assert.True(t, ok, "%s: expected '%s' to be kept", test.testDesc, chartName)
+		}
+		os.RemoveAll(dir)
+	}
+}
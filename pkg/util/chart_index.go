@@ -0,0 +1,74 @@
+/*
+Copyright (C) 2020 Synopsys, Inc.
+
+Licensed to the Apache Software Foundation (ASF) under one
+or more contributor license agreements. See the NOTICE file
+distributed with this work for additional information
+regarding copyright ownership. The ASF licenses this file
+to you under the Apache License, Version 2.0 (the
+"License"); you may not use this file except in compliance
+with the License. You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing,
+software distributed under the License is distributed on an
+"AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+KIND, either express or implied. See the License for the
+specific language governing permissions and limitations
+under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"helm.sh/helm/v3/pkg/repo"
+)
+
+// DefaultChartIndexTimeout is how long to wait for the chart repositories' indexes if a timeout isn't configured
+const DefaultChartIndexTimeout = 30 * time.Second
+
+// DefaultChartIndexTTL is how long the chart index in the chart cache is used before it's downloaded again
+const DefaultChartIndexTTL = 10 * time.Minute
+
+var chartIndexTimeout = DefaultChartIndexTimeout
+var chartIndexTTL = DefaultChartIndexTTL
+
+// SetChartIndexOptions sets how long to wait for the chart repositories' indexes and how long a
+// downloaded index is reused. A timeout or ttl of 0 disables it
+func SetChartIndexOptions(timeout, ttl time.Duration) {
+	chartIndexTimeout = timeout
+	chartIndexTTL = ttl
+}
+
+// loadChartIndex returns the index of the chart repositories at repositoryURLs. The index in the chart cache is used
+// if it was downloaded from the same repositories within the TTL, otherwise the index is downloaded and if the
+// repositories can't be reached in time the index in the chart cache is used regardless of its age. fetch has to
+// give up after the chart index timeout, which GetIndexFile sets on its HTTP client
+func loadChartIndex(repositoryURLs []string, fetch func() (*repo.IndexFile, error)) (*repo.IndexFile, error) {
+	source := strings.Join(repositoryURLs, ",")
+	if indexFile, ok := chartCache.LoadFreshIndexFile(source, chartIndexTTL); ok {
+		log.Debugf("using the chart index in the chart cache at '%s'", chartCache.Dir)
+		return indexFile, nil
+	}
+
+	indexFile, err := fetch()
+	if err != nil {
+		// Fall back to the chart cache when the chart repositories cannot be reached
+		cachedIndexFile, cacheErr := chartCache.LoadIndexFile()
+		if cacheErr != nil {
+			return nil, fmt.Errorf("unable to load the chart index from '%s': %s", source, err)
+		}
+		log.Warnf("using the chart cache at '%s' since the chart index couldn't be loaded from '%s': %s", chartCache.Dir, source, err)
+		return cachedIndexFile, nil
+	}
+	if err := chartCache.SaveIndexFile(indexFile, source); err != nil {
+		log.Debugf("unable to save the chart index to the chart cache: %+v", err)
+	}
+	return indexFile, nil
+}
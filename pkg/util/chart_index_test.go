@@ -0,0 +1,97 @@
+/*
+Copyright (C) 2020 Synopsys, Inc.
+
+Licensed to the Apache Software Foundation (ASF) under one
+or more contributor license agreements. See the NOTICE file
+distributed with this work for additional information
+regarding copyright ownership. The ASF licenses this file
+to you under the Apache License, Version 2.0 (the
+"License"); you may not use this file except in compliance
+with the License. You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing,
+software distributed under the License is distributed on an
+"AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+KIND, either express or implied. See the License for the
+specific language governing permissions and limitations
+under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/repo"
+)
+
+// newTestIndexFile returns an index with one version of a chart
+func newTestIndexFile(name, version string) *repo.IndexFile {
+	indexFile := repo.NewIndexFile()
+	indexFile.Add(&chart.Metadata{APIVersion: chart.APIVersionV2, Name: name, Version: version}, name+"-"+version+".tgz", "https://sig-repo.synopsys.com/sdsdockerrepo/helm-charts", "")
+	return indexFile
+}
+
+func TestLoadChartIndex(t *testing.T) {
+	dir, err := ioutil.TempDir("", "chart-index")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	SetChartCacheDir(dir)
+	defer SetChartCacheDir(DefaultChartCacheDir())
+	SetChartIndexOptions(100*time.Millisecond, time.Hour)
+	defer SetChartIndexOptions(DefaultChartIndexTimeout, DefaultChartIndexTTL)
+
+	repositoryURLs := []string{"https://sig-repo.synopsys.com/sdsdockerrepo/helm-charts"}
+	fetches := 0
+	fetch := func() (*repo.IndexFile, error) {
+		fetches++
+		return newTestIndexFile("blackduck", "2020.4.0"), nil
+	}
+
+	// The index is downloaded once and then reused from the chart cache
+	for i := 0; i < 2; i++ {
+		indexFile, err := loadChartIndex(repositoryURLs, fetch)
+		assert.NoError(t, err)
+		assert.True(t, indexFile.Has("blackduck", "2020.4.0"))
+	}
+	assert.Equal(t, 1, fetches)
+
+	// The cached index isn't used for different repositories
+	_, err = loadChartIndex([]string{"https://nexus-01.example.com/repository/helm"}, fetch)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, fetches)
+
+	// A stale index in the chart cache is used if the repositories can't be reached in time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(time.Second)
+		w.Write([]byte("apiVersion: v1\nentries: {}\n"))
+	}))
+	defer server.Close()
+	SetChartIndexOptions(100*time.Millisecond, 0)
+	start := time.Now()
+	indexFile, err := loadChartIndex(repositoryURLs, func() (*repo.IndexFile, error) {
+		return GetIndexFile(server.URL, nil)
+	})
+	assert.NoError(t, err)
+	assert.True(t, indexFile.Has("blackduck", "2020.4.0"))
+	assert.True(t, time.Since(start) < time.Second, "the index download didn't time out")
+
+	// The repositories' error is returned if the chart cache is empty
+	SetChartCacheDir(dir + "-missing")
+	_, err = loadChartIndex(repositoryURLs, func() (*repo.IndexFile, error) {
+		return nil, fmt.Errorf("no such host")
+	})
+	assert.EqualError(t, err, "unable to load the chart index from 'https://sig-repo.synopsys.com/sdsdockerrepo/helm-charts': no such host")
+}
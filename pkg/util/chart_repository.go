@@ -0,0 +1,191 @@
+/*
+Copyright (C) 2020 Synopsys, Inc.
+
+Licensed to the Apache Software Foundation (ASF) under one
+or more contributor license agreements. See the NOTICE file
+distributed with this work for additional information
+regarding copyright ownership. The ASF licenses this file
+to you under the Apache License, Version 2.0 (the
+"License"); you may not use this file except in compliance
+with the License. You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing,
+software distributed under the License is distributed on an
+"AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+KIND, either express or implied. See the License for the
+specific language governing permissions and limitations
+under the License.
+*/
+
+package util
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"helm.sh/helm/v3/pkg/getter"
+)
+
+// DefaultChartDownloadTimeout is how long to wait for a file from a chart repository
+const DefaultChartDownloadTimeout = 5 * time.Minute
+
+// ChartRepository is a chart repository that synopsysctl finds charts in
+type ChartRepository struct {
+	URL string `json:"url"`
+	// Username and Password are used for basic authentication
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	// Token is used for bearer token authentication
+	Token string `json:"token,omitempty"`
+	// CAFile is a PEM encoded CA bundle used to verify the repository's certificate
+	CAFile string `json:"caFile,omitempty"`
+	// CertFile and KeyFile are the PEM encoded client certificate and key used to authenticate with the repository
+	CertFile string `json:"certFile,omitempty"`
+	KeyFile  string `json:"keyFile,omitempty"`
+	// Priority orders the repositories, repositories with a higher priority are searched first
+	Priority int `json:"priority,omitempty"`
+}
+
+// chartRepositories are the chart repositories charts are found in, in the order they are searched
+var chartRepositories = []*ChartRepository{}
+
+// chartRepositoryInsecureSkipTLSVerify is true if the certificates of the chart repositories aren't verified
+var chartRepositoryInsecureSkipTLSVerify = false
+
+// SetChartRepositoryInsecureSkipTLSVerify sets whether the certificates of the chart repositories are verified
+func SetChartRepositoryInsecureSkipTLSVerify(insecureSkipTLSVerify bool) {
+	chartRepositoryInsecureSkipTLSVerify = insecureSkipTLSVerify
+}
+
+// SetChartRepositories sets the chart repositories that charts are found in
+func SetChartRepositories(repositories []*ChartRepository) error {
+	for _, repository := range repositories {
+		if len(repository.URL) == 0 {
+			return fmt.Errorf("a chart repository is missing its url")
+		}
+		if len(repository.Token) > 0 && (len(repository.Username) > 0 || len(repository.Password) > 0) {
+			return fmt.Errorf("chart repository '%s' can use a username and password or a token, but not both", repository.URL)
+		}
+		if (len(repository.CertFile) > 0) != (len(repository.KeyFile) > 0) {
+			return fmt.Errorf("chart repository '%s' needs both a client certificate and key, or neither", repository.URL)
+		}
+		repository.URL = strings.TrimSuffix(repository.URL, "/")
+	}
+	sortedRepositories := append([]*ChartRepository{}, repositories...)
+	sort.SliceStable(sortedRepositories, func(i, j int) bool {
+		return sortedRepositories[i].Priority > sortedRepositories[j].Priority
+	})
+	chartRepositories = sortedRepositories
+	return nil
+}
+
+// GetChartRepositories returns the chart repositories in the order they are searched
+func GetChartRepositories() []*ChartRepository {
+	return chartRepositories
+}
+
+// getChartRepositoryForURL returns the configured chart repository that serves url
+func getChartRepositoryForURL(url string) *ChartRepository {
+	var found *ChartRepository
+	for _, repository := range chartRepositories {
+		if url == repository.URL || strings.HasPrefix(url, repository.URL+"/") {
+			if found == nil || len(repository.URL) > len(found.URL) {
+				found = repository
+			}
+		}
+	}
+	return found
+}
+
+// getGettersForURL returns the getters used to download url within timeout, using the settings of
+// the chart repository that serves url if there is one
+func getGettersForURL(url string, timeout time.Duration) getter.Providers {
+	repository := getChartRepositoryForURL(url)
+	if repository == nil {
+		repository = &ChartRepository{URL: url}
+	}
+	return getter.Providers{
+		{
+			Schemes: []string{"http", "https"},
+			New: func(options ...getter.Option) (getter.Getter, error) {
+				return &chartRepositoryGetter{repository: repository, timeout: timeout}, nil
+			},
+		},
+	}
+}
+
+// chartRepositoryGetter downloads files from a chart repository with its authentication and TLS settings
+type chartRepositoryGetter struct {
+	repository *ChartRepository
+	// timeout is how long to wait for a file, 0 waits forever
+	timeout time.Duration
+}
+
+// Get downloads the file at href
+func (g *chartRepositoryGetter) Get(href string, options ...getter.Option) (*bytes.Buffer, error) {
+	client, err := g.httpClient()
+	if err != nil {
+		return nil, err
+	}
+	request, err := http.NewRequest(http.MethodGet, href, nil)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("User-Agent", "synopsysctl")
+	if len(g.repository.Token) > 0 {
+		request.Header.Set("Authorization", fmt.Sprintf("Bearer %s", g.repository.Token))
+	} else if len(g.repository.Username) > 0 || len(g.repository.Password) > 0 {
+		request.SetBasicAuth(g.repository.Username, g.repository.Password)
+	}
+
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch %s : %s", href, response.Status)
+	}
+	buf := bytes.NewBuffer(nil)
+	_, err = buf.ReadFrom(response.Body)
+	return buf, err
+}
+
+// httpClient returns an http.Client with the getter's timeout that trusts the chart repository's CA bundle and
+// authenticates with its client certificate
+func (g *chartRepositoryGetter) httpClient() (*http.Client, error) {
+	tlsConfig := &tls.Config{InsecureSkipVerify: chartRepositoryInsecureSkipTLSVerify}
+	if len(g.repository.CAFile) > 0 {
+		caBundle, err := ioutil.ReadFile(g.repository.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the CA bundle '%s' due to %+v", g.repository.CAFile, err)
+		}
+		certPool, err := x509.SystemCertPool()
+		if err != nil || certPool == nil {
+			certPool = x509.NewCertPool()
+		}
+		if !certPool.AppendCertsFromPEM(caBundle) {
+			return nil, fmt.Errorf("the CA bundle '%s' doesn't contain any PEM encoded certificates", g.repository.CAFile)
+		}
+		tlsConfig.RootCAs = certPool
+	}
+	if len(g.repository.CertFile) > 0 {
+		certificate, err := tls.LoadX509KeyPair(g.repository.CertFile, g.repository.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load the client certificate '%s' and key '%s' due to %+v", g.repository.CertFile, g.repository.KeyFile, err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	return &http.Client{Timeout: g.timeout, Transport: transport}, nil
+}
@@ -0,0 +1,158 @@
+/*
+Copyright (C) 2020 Synopsys, Inc.
+
+Licensed to the Apache Software Foundation (ASF) under one
+or more contributor license agreements. See the NOTICE file
+distributed with this work for additional information
+regarding copyright ownership. The ASF licenses this file
+to you under the Apache License, Version 2.0 (the
+"License"); you may not use this file except in compliance
+with the License. You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing,
+software distributed under the License is distributed on an
+"AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+KIND, either express or implied. See the License for the
+specific language governing permissions and limitations
+under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/downloader"
+	"helm.sh/helm/v3/pkg/getter"
+	"helm.sh/helm/v3/pkg/provenance"
+	"helm.sh/helm/v3/pkg/repo"
+)
+
+// chartProvenanceFileExtension is appended to a chart's URL or path to get its provenance file
+const chartProvenanceFileExtension = ".prov"
+
+// verifyCharts is true if charts must be verified before they are installed or upgraded
+var verifyCharts = false
+
+// chartKeyring is the PGP keyring used to verify the signatures of the charts
+var chartKeyring = DefaultChartKeyring()
+
+// DefaultChartKeyring returns the keyring used to verify charts if one isn't configured
+func DefaultChartKeyring() string {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(homeDir, ".gnupg", "pubring.gpg")
+}
+
+// SetChartVerification sets whether charts are verified with keyring before they are installed or upgraded
+func SetChartVerification(verify bool, keyring string) {
+	verifyCharts = verify
+	chartKeyring = keyring
+}
+
+// VerifyChart checks the signature in the provenance file of the chart at chartPath against the keyring
+// and that the chart's SHA-256 digest matches the chart repository's index. chartURL is where the chart
+// was downloaded from and is used to download the provenance file if it isn't next to chartPath
+func VerifyChart(chartURL, chartPath, keyring string) error {
+	if len(keyring) == 0 {
+		return fmt.Errorf("a keyring is required to verify '%s'", chartURL)
+	}
+	if _, err := os.Stat(keyring); err != nil {
+		return fmt.Errorf("unable to find the keyring '%s' due to %+v", keyring, err)
+	}
+
+	provenancePath := chartPath + chartProvenanceFileExtension
+	if _, err := os.Stat(provenancePath); err != nil {
+		if err := downloadChartProvenance(chartURL, provenancePath); err != nil {
+			return err
+		}
+	}
+
+	// Verify the chart was signed by a key in the keyring and hasn't been modified since
+	verification, err := downloader.VerifyChart(chartPath, keyring)
+	if err != nil {
+		return fmt.Errorf("failed to verify the signature of '%s' due to %s", chartURL, err)
+	}
+
+	// Verify the chart is the one the chart repository serves
+	chart, err := loader.Load(chartPath)
+	if err != nil {
+		return fmt.Errorf("failed to load '%s' due to %s", chartPath, err)
+	}
+	indexDigest, err := getChartDigestFromIndex(chart.Metadata.Name, chart.Metadata.Version)
+	if err != nil {
+		return err
+	}
+	digest, err := provenance.DigestFile(chartPath)
+	if err != nil {
+		return fmt.Errorf("failed to compute the digest of '%s' due to %s", chartPath, err)
+	}
+	if digest != indexDigest {
+		return fmt.Errorf("the digest of '%s' (sha256:%s) doesn't match the chart repository index (sha256:%s)", chartURL, digest, indexDigest)
+	}
+
+	log.Debugf("verified '%s' signed by %s with digest sha256:%s", chartURL, getSignerName(verification), digest)
+	return nil
+}
+
+// downloadChartProvenance downloads the provenance file of the chart at chartURL to provenancePath
+func downloadChartProvenance(chartURL, provenancePath string) error {
+	provenanceURL := chartURL + chartProvenanceFileExtension
+	u, err := url.Parse(provenanceURL)
+	if err != nil || len(u.Scheme) == 0 {
+		return fmt.Errorf("unable to find the provenance file '%s'", provenancePath)
+	}
+	g, err := getGettersForURL(provenanceURL, DefaultChartDownloadTimeout).ByScheme(u.Scheme)
+	if err != nil {
+		return fmt.Errorf("unable to download '%s': %s", provenanceURL, err)
+	}
+	data, err := g.Get(provenanceURL, getter.WithURL(provenanceURL))
+	if err != nil {
+		return fmt.Errorf("failed to download the provenance file '%s' due to %s", provenanceURL, err)
+	}
+	if err := ioutil.WriteFile(provenancePath, data.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write '%s' due to %+v", provenancePath, err)
+	}
+	return nil
+}
+
+// getChartDigestFromIndex returns the SHA-256 digest of a chart version in the chart repository's index,
+// using the index in the chart cache before downloading the index from the chart repositories
+func getChartDigestFromIndex(name, version string) (string, error) {
+	if indexFile, err := repo.LoadIndexFile(chartCache.IndexFilePath()); err == nil {
+		if chartVersion, err := indexFile.Get(name, version); err == nil && len(chartVersion.Digest) > 0 {
+			return strings.TrimPrefix(chartVersion.Digest, "sha256:"), nil
+		}
+	}
+	indexFile, err := getIndexFileFromChartRepositories(nil)
+	if err != nil {
+		return "", fmt.Errorf("unable to get the digest of %s-%s from the chart repository index: %s", name, version, err)
+	}
+	chartVersion, err := indexFile.Get(name, version)
+	if err != nil || len(chartVersion.Digest) == 0 {
+		return "", fmt.Errorf("the chart repository index doesn't have a digest for %s-%s", name, version)
+	}
+	return strings.TrimPrefix(chartVersion.Digest, "sha256:"), nil
+}
+
+// getSignerName returns the names of the identities that signed a chart
+func getSignerName(verification *provenance.Verification) string {
+	names := []string{}
+	if verification.SignedBy != nil {
+		for name := range verification.SignedBy.Identities {
+			names = append(names, name)
+		}
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,140 @@
+/*
+Copyright (C) 2020 Synopsys, Inc.
+
+Licensed to the Apache Software Foundation (ASF) under one
+or more contributor license agreements. See the NOTICE file
+distributed with this work for additional information
+regarding copyright ownership. The ASF licenses this file
+to you under the Apache License, Version 2.0 (the
+"License"); you may not use this file except in compliance
+with the License. You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing,
+software distributed under the License is distributed on an
+"AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+KIND, either express or implied. See the License for the
+specific language governing permissions and limitations
+under the License.
+*/
+
+package util
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/openpgp"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/provenance"
+	"helm.sh/helm/v3/pkg/repo"
+)
+
+// writeTestKeyring creates a PGP key and writes its public key to a keyring at keyringPath
+func writeTestKeyring(t *testing.T, keyringPath string) *openpgp.Entity {
+	entity, err := openpgp.NewEntity("Synopsys", "", "charts@example.com", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyring, err := os.Create(keyringPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer keyring.Close()
+	if err := entity.Serialize(keyring); err != nil {
+		t.Fatal(err)
+	}
+	return entity
+}
+
+// saveTestIndexFile stores an index in the chart cache with digest for the chart name-version
+func saveTestIndexFile(t *testing.T, name, version, digest string) {
+	indexFile := repo.NewIndexFile()
+	indexFile.Add(&chart.Metadata{APIVersion: chart.APIVersionV2, Name: name, Version: version}, name+"-"+version+".tgz", "https://sig-repo.synopsys.com/sdsdockerrepo/helm-charts", digest)
+	if err := chartCache.SaveIndexFile(indexFile, "https://sig-repo.synopsys.com/sdsdockerrepo/helm-charts"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestVerifyChart(t *testing.T) {
+	dir, err := ioutil.TempDir("", "chart-verification")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	SetChartCacheDir(dir)
+	defer SetChartCacheDir(DefaultChartCacheDir())
+
+	saveTestChart(t, dir, "blackduck", "2020.4.0", "2020.4.0")
+	chartPath := filepath.Join(dir, "blackduck-2020.4.0.tgz")
+	chartURL := "https://sig-repo.synopsys.com/sdsdockerrepo/helm-charts/blackduck-2020.4.0.tgz"
+	digest, err := provenance.DigestFile(chartPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Sign the chart
+	keyringPath := filepath.Join(dir, "pubring.gpg")
+	entity := writeTestKeyring(t, keyringPath)
+	signatory := &provenance.Signatory{Entity: entity, KeyRing: openpgp.EntityList{entity}}
+	signature, err := signatory.ClearSign(chartPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(chartPath+".prov", []byte(signature), 0644); err != nil {
+		t.Fatal(err)
+	}
+	otherKeyringPath := filepath.Join(dir, "other-pubring.gpg")
+	writeTestKeyring(t, otherKeyringPath)
+
+	type test struct {
+		testDesc    string
+		keyring     string
+		indexDigest string
+		shouldFail  bool
+	}
+	tests := []test{
+		{
+			testDesc:    "signed chart that matches the index",
+			keyring:     keyringPath,
+			indexDigest: digest,
+			shouldFail:  false,
+		},
+		{
+			testDesc:    "chart signed by a key that isn't in the keyring",
+			keyring:     otherKeyringPath,
+			indexDigest: digest,
+			shouldFail:  true,
+		},
+		{
+			testDesc:    "chart that doesn't match the index",
+			keyring:     keyringPath,
+			indexDigest: "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+			shouldFail:  true,
+		},
+		{
+			testDesc:    "missing keyring",
+			keyring:     filepath.Join(dir, "missing.gpg"),
+			indexDigest: digest,
+			shouldFail:  true,
+		},
+	}
+
+	for _, test := range tests {
+		saveTestIndexFile(t, "blackduck", "2020.4.0", test.indexDigest)
+		err := VerifyChart(chartURL, chartPath, test.keyring)
+		if test.shouldFail {
+			assert.Error(t, err, test.testDesc)
+		} else {
+			assert.NoError(t, err, test.testDesc)
+		}
+	}
+
+	// Charts without a provenance file can't be verified
+	saveTestChart(t, dir, "alert", "5.3.0", "5.3.0")
+	assert.Error(t, VerifyChart(filepath.Join(dir, "alert-5.3.0.tgz"), filepath.Join(dir, "alert-5.3.0.tgz"), keyringPath))
+}
@@ -1366,6 +1366,30 @@ func PatchDeployment(clientset *kubernetes.Clientset, old appsv1.Deployment, new
 	return nil
 }
 
+// RestartedAtAnnotation is set on the pod template of a deployment to restart its pods
+const RestartedAtAnnotation = "synopsys.com/restartedAt"
+
+// RestartDeployment triggers a rolling restart of a deployment by patching an annotation on its pod template.
+// Unlike PatchDeployment, the replicas aren't scaled down, so the deployment stays available during the restart
+func RestartDeployment(clientset *kubernetes.Clientset, old appsv1.Deployment) (*appsv1.Deployment, error) {
+	oldData, err := json.Marshal(old)
+	if err != nil {
+		return nil, err
+	}
+	new := old.DeepCopy()
+	new.Spec.Template.Annotations = InitAnnotations(new.Spec.Template.Annotations)
+	new.Spec.Template.Annotations[RestartedAtAnnotation] = time.Now().Format(time.RFC3339)
+	newData, err := json.Marshal(new)
+	if err != nil {
+		return nil, err
+	}
+	patchBytes, err := strategicpatch.CreateTwoWayMergePatch(oldData, newData, appsv1.Deployment{})
+	if err != nil {
+		return nil, err
+	}
+	return clientset.AppsV1().Deployments(new.Namespace).Patch(new.Name, types.StrategicMergePatchType, patchBytes)
+}
+
 // GetCustomResourceDefinition get the custom resource defintion
 func GetCustomResourceDefinition(apiExtensionClient *apiextensionsclient.Clientset, name string) (*apiextensions.CustomResourceDefinition, error) {
 	return apiExtensionClient.ApiextensionsV1beta1().CustomResourceDefinitions().Get(name, metav1.GetOptions{})
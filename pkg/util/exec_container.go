@@ -76,3 +76,63 @@ func ExecContainer(kubeConfig *rest.Config, request *rest.Request, command []str
 	log.Debugf("stdout: %s, stderr: %s", stdout.String(), stderr.String())
 	return stdout.String(), err
 }
+
+// CreateAttachContainerRequest will create the request to attach to the stdin of the Kubernetes pod
+func CreateAttachContainerRequest(clientset *kubernetes.Clientset, pod *corev1.Pod) *rest.Request {
+	return clientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Name(pod.Name).
+		Namespace(pod.Namespace).
+		SubResource("attach").
+		VersionedParams(&corev1.PodAttachOptions{
+			Container: pod.Spec.Containers[0].Name,
+			Stdin:     true,
+			Stdout:    false,
+			Stderr:    false,
+			TTY:       false,
+		}, scheme.ParameterCodec)
+}
+
+// AttachContainer will attach to the container and copy the input to its stdin until the end of the input
+func AttachContainer(kubeConfig *rest.Config, request *rest.Request, stdin io.Reader) error {
+	attach, err := remotecommand.NewSPDYExecutor(kubeConfig, "POST", request.URL())
+	if err != nil {
+		return err
+	}
+	return attach.Stream(remotecommand.StreamOptions{
+		Stdin: stdin,
+		Tty:   false,
+	})
+}
+
+// CreateExecCommandRequest will create the request to run the command in the Kubernetes pod and stream its output
+func CreateExecCommandRequest(clientset *kubernetes.Clientset, pod *corev1.Pod, command []string) *rest.Request {
+	return clientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Name(pod.Name).
+		Namespace(pod.Namespace).
+		SubResource("exec").
+		Param("container", pod.Spec.Containers[0].Name).
+		VersionedParams(&corev1.PodExecOptions{
+			Container: pod.Spec.Containers[0].Name,
+			Command:   command,
+			Stdin:     false,
+			Stdout:    true,
+			Stderr:    true,
+			TTY:       false,
+		}, scheme.ParameterCodec)
+}
+
+// StreamContainer will run the exec request and copy the output of the command to stdout and stderr until it exits. It
+// returns an error if the command exits with a non-zero status
+func StreamContainer(kubeConfig *rest.Config, request *rest.Request, stdout io.Writer, stderr io.Writer) error {
+	exec, err := remotecommand.NewSPDYExecutor(kubeConfig, "POST", request.URL())
+	if err != nil {
+		return err
+	}
+	return exec.Stream(remotecommand.StreamOptions{
+		Stdout: stdout,
+		Stderr: stderr,
+		Tty:    false,
+	})
+}
@@ -28,7 +28,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"os"
+	"path"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -39,8 +42,8 @@ import (
 	"helm.sh/helm/v3/pkg/action"
 	"helm.sh/helm/v3/pkg/chart"
 	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
 	"helm.sh/helm/v3/pkg/cli"
-	"helm.sh/helm/v3/pkg/getter"
 	"helm.sh/helm/v3/pkg/kube"
 	"helm.sh/helm/v3/pkg/release"
 	"helm.sh/helm/v3/pkg/repo"
@@ -161,6 +164,27 @@ func UpdateWithHelm3(releaseName, namespace, chartURL string, vals map[string]in
 	return nil
 }
 
+// UpdateValuesWithHelm3 uses the helm NewUpgrade action to update the values of a resource in the cluster with the
+// chart that it's already deployed with, so the chart doesn't have to be located again
+func UpdateValuesWithHelm3(releaseName, namespace string, vals map[string]interface{}, kubeConfig string) error {
+	actionConfig, err := CreateHelmActionConfiguration(kubeConfig, "", namespace)
+	if err != nil {
+		return err
+	}
+	currentRelease, err := action.NewGet(actionConfig).Run(releaseName)
+	if err != nil {
+		return fmt.Errorf("release '%s' does not exist", releaseName)
+	}
+
+	client := action.NewUpgrade(actionConfig)
+	client.Namespace = namespace
+	client.ResetValues = true
+	if _, err := client.Run(releaseName, currentRelease.Chart, vals); err != nil {
+		return fmt.Errorf("failed to run upgrade: %s", err)
+	}
+	return nil
+}
+
 // TemplateWithHelm3 prints the kube manifest files for a resource
 func TemplateWithHelm3(releaseName, namespace, chartURL string, vals map[string]interface{}, extraFiles ...string) error {
 	actionConfig, err := CreateHelmActionConfiguration("", "", namespace)
@@ -193,10 +217,32 @@ func TemplateWithHelm3(releaseName, namespace, chartURL string, vals map[string]
 // RenderManifests converts a helm chart to a string of the kube manifest files
 // Modified from https://github.com/openshift/console/blob/cdf6b189b71e488033ecaba7d90258d9f9453478/pkg/helm/actions/template_test.go
 func RenderManifests(releaseName, namespace string, chart *chart.Chart, vals map[string]interface{}, actionConfig *action.Configuration) (string, error) {
-	validate := false
 	includeCrds := true
 	emptyResponse := ""
 
+	client, rel, err := renderRelease(releaseName, namespace, chart, vals, actionConfig, includeCrds)
+	if err != nil {
+		return emptyResponse, err
+	}
+
+	var manifests bytes.Buffer
+	var output bytes.Buffer
+
+	fmt.Fprintln(&manifests, strings.TrimSpace(rel.Manifest))
+
+	if !client.DisableHooks {
+		for _, m := range rel.Hooks {
+			fmt.Fprintf(&manifests, "---\n# Source: %s\n%s\n", m.Path, m.Manifest)
+		}
+	}
+	fmt.Fprintf(&output, "%s", manifests.String())
+	return output.String(), nil
+}
+
+// renderRelease runs a client only dry run install of the chart and returns the release it would create
+func renderRelease(releaseName, namespace string, chart *chart.Chart, vals map[string]interface{}, actionConfig *action.Configuration, includeCrds bool) (*action.Install, *release.Release, error) {
+	validate := false
+
 	client := action.NewInstall(actionConfig)
 	if client.Version == "" && client.Devel {
 		client.Version = ">0.0.0-0"
@@ -210,21 +256,99 @@ func RenderManifests(releaseName, namespace string, chart *chart.Chart, vals map
 
 	rel, err := client.Run(chart, vals)
 	if err != nil {
-		return emptyResponse, err
+		return nil, nil, err
 	}
+	return client, rel, nil
+}
 
-	var manifests bytes.Buffer
-	var output bytes.Buffer
+// GetImagesFromChart returns the images referenced by the kube manifest files of the chart at chartURL
+func GetImagesFromChart(releaseName, namespace, chartURL string, vals map[string]interface{}, extraFiles ...string) ([]string, error) {
+	actionConfig, err := CreateHelmActionConfiguration("", "", namespace)
+	if err != nil {
+		return nil, err
+	}
+	chart, err := LoadChart(chartURL, actionConfig)
+	if err != nil {
+		return nil, err
+	}
 
-	fmt.Fprintln(&manifests, strings.TrimSpace(rel.Manifest))
+	fileValues := map[string]interface{}{}
+	if err := mergeValuesWithExtraFilesFromChart(chart, fileValues, extraFiles); err != nil {
+		return nil, fmt.Errorf("failed to merge extra configuration files due to %s", err)
+	}
+	vals = MergeMaps(fileValues, vals)
 
-	if !client.DisableHooks {
-		for _, m := range rel.Hooks {
-			fmt.Fprintf(&manifests, "---\n# Source: %s\n%s\n", m.Path, m.Manifest)
+	manifests, err := RenderManifests(releaseName, namespace, chart, vals, actionConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render kube manifest files due to %s", err)
+	}
+	return ParseImagesFromManifests(manifests), nil
+}
+
+// GetAllImagesFromChart returns the images that the chart at chartURL can use, including the images of the
+// components that are off by default
+func GetAllImagesFromChart(releaseName, namespace, chartURL string) ([]string, error) {
+	actionConfig, err := CreateHelmActionConfiguration("", "", namespace)
+	if err != nil {
+		return nil, err
+	}
+	chart, err := LoadChart(chartURL, actionConfig)
+	if err != nil {
+		return nil, err
+	}
+	return getAllChartImages(releaseName, namespace, chart, actionConfig)
+}
+
+// getAllChartImages renders the chart with its default values, with each of its toggles (the values that are false
+// by default) turned on, and with all of them turned on, and returns the images referenced by any of the manifests
+func getAllChartImages(releaseName, namespace string, chart *chart.Chart, actionConfig *action.Configuration) ([]string, error) {
+	manifests, err := RenderManifests(releaseName, namespace, chart, map[string]interface{}{}, actionConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render kube manifest files due to %s", err)
+	}
+
+	toggles := getHelmValueToggles("", chart.Values)
+	allToggles := map[string]interface{}{}
+	valuesList := []map[string]interface{}{}
+	for _, toggle := range toggles {
+		vals := map[string]interface{}{}
+		SetHelmValueInMap(vals, strings.Split(toggle, "."), true)
+		SetHelmValueInMap(allToggles, strings.Split(toggle, "."), true)
+		valuesList = append(valuesList, vals)
+	}
+	if len(toggles) > 1 {
+		valuesList = append(valuesList, allToggles)
+	}
+	// A toggle may need other values to render, the images of the other toggles are still found
+	for _, vals := range valuesList {
+		toggleManifests, err := RenderManifests(releaseName, namespace, chart, vals, actionConfig)
+		if err != nil {
+			log.Debugf("unable to render the manifests with %+v due to %s", vals, err)
+			continue
 		}
+		manifests = manifests + "\n---\n" + toggleManifests
 	}
-	fmt.Fprintf(&output, "%s", manifests.String())
-	return output.String(), nil
+	return ParseImagesFromManifests(manifests), nil
+}
+
+// getHelmValueToggles returns the sorted dotted keys of the values that are false
+func getHelmValueToggles(prefix string, values map[string]interface{}) []string {
+	toggles := []string{}
+	for key, value := range values {
+		if len(prefix) > 0 {
+			key = fmt.Sprintf("%s.%s", prefix, key)
+		}
+		switch value := value.(type) {
+		case bool:
+			if !value {
+				toggles = append(toggles, key)
+			}
+		case map[string]interface{}:
+			toggles = append(toggles, getHelmValueToggles(key, value)...)
+		}
+	}
+	sort.Strings(toggles)
+	return toggles
 }
 
 // DeleteWithHelm3 uses the helm NewUninstall action to delete a resource from the cluster
@@ -244,6 +368,71 @@ func DeleteWithHelm3(releaseName, namespace, kubeConfig string) error {
 	return nil
 }
 
+// ListWithHelm3 uses the helm NewList action to return the releases in the namespace, or in all
+// namespaces if namespace is empty
+func ListWithHelm3(namespace, kubeConfig string) ([]*release.Release, error) {
+	actionConfig, err := CreateHelmActionConfiguration(kubeConfig, "", namespace)
+	if err != nil {
+		return nil, err
+	}
+	client := action.NewList(actionConfig)
+	client.AllNamespaces = len(namespace) == 0
+	releases, err := client.Run()
+	if err != nil {
+		return nil, fmt.Errorf("failed to run list due to %s", err)
+	}
+	return releases, nil
+}
+
+// RollbackWithHelm3 uses the helm NewRollback action to roll back a release to a revision,
+// revision 0 rolls back to the previous revision
+func RollbackWithHelm3(releaseName, namespace string, revision int, kubeConfig string) error {
+	actionConfig, err := CreateHelmActionConfiguration(kubeConfig, "", namespace)
+	if err != nil {
+		return err
+	}
+	if releaseExists := ReleaseExists(releaseName, namespace, kubeConfig); !releaseExists {
+		return fmt.Errorf("release '%s' does not exist", releaseName)
+	}
+	client := action.NewRollback(actionConfig)
+	client.Version = revision
+	if err := client.Run(releaseName); err != nil { // rolls back the releaseName in the namespace from the actionConfig
+		return fmt.Errorf("failed to run rollback due to %s", err)
+	}
+	return nil
+}
+
+// HistoryWithHelm3 uses the helm NewHistory action to return the revisions of a release, oldest first
+func HistoryWithHelm3(releaseName, namespace, kubeConfig string) ([]*release.Release, error) {
+	actionConfig, err := CreateHelmActionConfiguration(kubeConfig, "", namespace)
+	if err != nil {
+		return nil, err
+	}
+	client := action.NewHistory(actionConfig)
+	client.Max = 256
+	revisions, err := client.Run(releaseName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to run history due to %s", err)
+	}
+	sort.Slice(revisions, func(i, j int) bool { return revisions[i].Version < revisions[j].Version })
+	return revisions, nil
+}
+
+// GetRevisionWithHelm3 uses the helm NewGet action to return a revision of a release
+func GetRevisionWithHelm3(releaseName, namespace string, revision int, kubeConfig string) (*release.Release, error) {
+	actionConfig, err := CreateHelmActionConfiguration(kubeConfig, "", namespace)
+	if err != nil {
+		return nil, err
+	}
+	client := action.NewGet(actionConfig)
+	client.Version = revision
+	rel, err := client.Run(releaseName)
+	if err != nil {
+		return nil, fmt.Errorf("unable to find revision %d of release '%s' in namespace '%s': %s", revision, releaseName, namespace, err)
+	}
+	return rel, nil
+}
+
 // GetWithHelm3 uses the helm NewGet action to return a Release with information about
 // a resource from the cluster
 func GetWithHelm3(releaseName, namespace, kubeConfig string) (*release.Release, error) {
@@ -332,12 +521,16 @@ func GetActionConfigurations(host, namespace, token string, transport *http.Roun
 // LoadChart returns a chart from the specified chartURL
 // Modified from https://github.com/openshift/console/blob/master/pkg/helm/actions/template_test.go
 func LoadChart(chartURL string, actionConfig *action.Configuration) (*chart.Chart, error) {
-	client := action.NewInstall(actionConfig)
-
-	// Get full path - checks local machine and chart repository
-	chartFullPath, err := client.ChartPathOptions.LocateChart(chartURL, settings)
+	chartFullPath, err := locateChart(chartURL, actionConfig)
 	if err != nil {
-		return nil, fmt.Errorf("failed to locate resources at '%s' due to %s", chartURL, err)
+		return nil, err
+	}
+
+	// Refuse to use charts that can't be verified
+	if verifyCharts {
+		if err := VerifyChart(chartURL, chartFullPath, chartKeyring); err != nil {
+			return nil, err
+		}
 	}
 
 	chart, err := loader.Load(chartFullPath)
@@ -347,6 +540,31 @@ func LoadChart(chartURL string, actionConfig *action.Configuration) (*chart.Char
 	return chart, nil
 }
 
+// locateChart returns the path to the chart at chartURL, downloading it if it isn't on the local machine
+func locateChart(chartURL string, actionConfig *action.Configuration) (string, error) {
+	// Resolve charts from the chart cache before going to the chart repository (skip local chart paths)
+	if _, err := os.Stat(chartURL); err != nil {
+		if cachedChartPath, ok := chartCache.Lookup(chartURL); ok {
+			log.Debugf("loading '%s' from the chart cache at '%s'", chartURL, cachedChartPath)
+			return cachedChartPath, nil
+		}
+		cachedChartPath, err := chartCache.Pull(chartURL)
+		if err == nil {
+			return cachedChartPath, nil
+		}
+		log.Debugf("unable to add '%s' to the chart cache: %+v", chartURL, err)
+	}
+
+	client := action.NewInstall(actionConfig)
+
+	// Get full path - checks local machine and chart repository
+	chartFullPath, err := client.ChartPathOptions.LocateChart(chartURL, settings)
+	if err != nil {
+		return "", fmt.Errorf("failed to locate resources at '%s' due to %s", chartURL, err)
+	}
+	return chartFullPath, nil
+}
+
 // ParseChartVersion ...
 func ParseChartVersion(chartURL string) string {
 	chartPackageSplit := ParsePackageName(chartURL)
@@ -421,12 +639,29 @@ func GetLatestChartURLForApp(chartURLs []string, appName string) (string, error)
 	return latestURL, nil
 }
 
+// GetAppVersions returns the app versions that have a chart for appName, newest first
+func GetAppVersions(chartURLs []string, appName string) []string {
+	appVersions := []string{}
+	found := map[string]bool{}
+	for _, url := range chartURLs {
+		packageNameSlice := ParsePackageName(url)
+		if packageNameSlice[0] == appName && !found[packageNameSlice[1]] {
+			found[packageNameSlice[1]] = true
+			appVersions = append(appVersions, packageNameSlice[1])
+		}
+	}
+	sort.SliceStable(appVersions, func(i, j int) bool {
+		return CompareVersions(appVersions[i], appVersions[j]) > 0
+	})
+	return appVersions
+}
+
 // ParsePackageName returns {app-name, app-version, chart-num}
 // synopsys-alert-5.3.1-12 -> [synopsys-alert-5.3.1-12 synopsys-alert 5.3.1 -12 12]
 // blackduck-2020.4.2 -> [blackduck-2020.4.2 blackduck 2020.4.2  ]
 func ParsePackageName(chartURL string) []string {
 	packageNameRegexp := regexp.MustCompile(`([a-z\-]+)-([0-9\.]*[0-9]+)(-([0-9]+))?`)
-	packageSubstringSubmatch := packageNameRegexp.FindStringSubmatch(chartURL)
+	packageSubstringSubmatch := packageNameRegexp.FindStringSubmatch(path.Base(chartURL)) // only the file name, hosts and paths of mirrors can look like packages
 	parsedOutput := []string{"", "", ""}
 	if len(packageSubstringSubmatch) > 2 {
 		parsedOutput[0] = packageSubstringSubmatch[1]
@@ -440,6 +675,7 @@ func ParsePackageName(chartURL string) []string {
 }
 
 // GetChartURLs ...
+// if repoURL == "" then it searches all of the chart repositories from SetChartRepositories
 // if appName == "" then it returns all chart URLs (as seen in Chart.yaml)
 // (NOTE: if the package name does not match name in Chart.yaml exactly then it will not be returned)
 func GetChartURLs(repoURL, appName string) ([]string, error) {
@@ -450,7 +686,20 @@ func GetChartURLs(repoURL, appName string) ([]string, error) {
 		return chartURLs, err
 	}
 
-	indexFile, err := GetIndexFile(repoURL, actionConfig)
+	var indexFile *repo.IndexFile
+	if len(repoURL) > 0 {
+		indexFile, err = loadChartIndex([]string{repoURL}, func() (*repo.IndexFile, error) {
+			return GetIndexFile(repoURL, actionConfig)
+		})
+	} else {
+		repositoryURLs := []string{}
+		for _, repository := range chartRepositories {
+			repositoryURLs = append(repositoryURLs, repository.URL)
+		}
+		indexFile, err = loadChartIndex(repositoryURLs, func() (*repo.IndexFile, error) {
+			return getIndexFileFromChartRepositories(actionConfig)
+		})
+	}
 	if err != nil {
 		return chartURLs, err
 	}
@@ -473,6 +722,30 @@ func GetChartURLs(repoURL, appName string) ([]string, error) {
 	return chartURLs, nil
 }
 
+// getIndexFileFromChartRepositories merges the indexes of the chart repositories from SetChartRepositories.
+// If a chart version is in more than one repository then the one from the repository with the highest priority is used
+func getIndexFileFromChartRepositories(actionConfig *action.Configuration) (*repo.IndexFile, error) {
+	if len(chartRepositories) == 0 {
+		return nil, fmt.Errorf("no chart repositories are configured")
+	}
+	indexFile := repo.NewIndexFile()
+	errs := []string{}
+	for _, repository := range chartRepositories {
+		repositoryIndexFile, err := GetIndexFile(repository.URL, actionConfig)
+		if err != nil {
+			log.Debugf("skipping chart repository '%s': %s", repository.URL, err)
+			errs = append(errs, err.Error())
+			continue
+		}
+		indexFile.Merge(repositoryIndexFile)
+	}
+	if len(errs) == len(chartRepositories) {
+		return nil, fmt.Errorf("unable to reach any chart repository: %s", strings.Join(errs, "; "))
+	}
+	indexFile.SortEntries()
+	return indexFile, nil
+}
+
 // GetIndexFile ...
 func GetIndexFile(repoURL string, actionConfig *action.Configuration) (*repo.IndexFile, error) {
 	client := action.NewInstall(actionConfig)
@@ -488,7 +761,8 @@ func GetIndexFile(repoURL string, actionConfig *action.Configuration) (*repo.Ind
 	keyFile := client.ChartPathOptions.KeyFile
 	caFile := client.ChartPathOptions.CaFile
 
-	getters := getter.All(settings)
+	// the index is downloaded within the chart index timeout, so that the chart cache can be used in time
+	getters := getGettersForURL(repoURL, chartIndexTimeout)
 
 	// Download and write the index file to a temporary location
 	buf := make([]byte, 20)
@@ -519,6 +793,17 @@ func GetIndexFile(repoURL string, actionConfig *action.Configuration) (*repo.Ind
 		return nil, err
 	}
 
+	// Chart repositories such as Artifactory and Nexus use chart URLs that are relative to the repository
+	for _, chartVersions := range repoIndex.Entries {
+		for _, chartVersion := range chartVersions {
+			for i, url := range chartVersion.URLs {
+				if absoluteURL, err := repo.ResolveReferenceURL(repoURL, url); err == nil {
+					chartVersion.URLs[i] = absoluteURL
+				}
+			}
+		}
+	}
+
 	return repoIndex, nil
 }
 
@@ -662,6 +947,101 @@ func MergeMaps(a, b map[string]interface{}) map[string]interface{} {
 	return out
 }
 
+// ReadValuesFiles returns the values in the YAML files at paths merged in order, values in later files take precedence
+func ReadValuesFiles(paths []string) (map[string]interface{}, error) {
+	values := map[string]interface{}{}
+	for _, path := range paths {
+		fileValues, err := chartutil.ReadValuesFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the values file '%s' due to %s", path, err)
+		}
+		values = MergeMaps(values, fileValues)
+	}
+	return values, nil
+}
+
+// sensitiveHelmValueKeys are the (lower case) parts of the keys of Helm values that hold secrets
+var sensitiveHelmValueKeys = []string{"password", "sealkey", "token", "privatekey", "certificate", "apikey"}
+
+// secretReferenceHelmValueKey is the (lower case) end of the keys of Helm values that hold the name of a secret,
+// which are references to secrets rather than secrets (e.g. tlsCertSecretName)
+const secretReferenceHelmValueKey = "secretname"
+
+// RedactHelmValues returns a copy of values without the values that hold secrets, such as passwords,
+// seal keys and certificates, and the keys of the values that were removed (e.g. postgres.adminPassword).
+// The names of secrets are kept so that the values still refer to them
+func RedactHelmValues(values map[string]interface{}) (map[string]interface{}, []string) {
+	redactedValues := map[string]interface{}{}
+	redactedKeys := []string{}
+	for key, value := range values {
+		if isSensitiveHelmValueKey(key) && !isEmptyHelmValue(value) {
+			redactedKeys = append(redactedKeys, key)
+			continue
+		}
+		redactedValue, nestedKeys := redactHelmValue(value)
+		redactedValues[key] = redactedValue
+		for _, nestedKey := range nestedKeys {
+			redactedKeys = append(redactedKeys, fmt.Sprintf("%s%s", key, nestedKey))
+		}
+	}
+	sort.Strings(redactedKeys)
+	return redactedValues, redactedKeys
+}
+
+// redactHelmValue redacts the secrets in the maps and lists of a Helm value, and returns the keys of the secrets
+// relative to the value (e.g. .adminPassword or [0].password)
+func redactHelmValue(value interface{}) (interface{}, []string) {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		redactedValues, redactedKeys := RedactHelmValues(value)
+		for i, key := range redactedKeys {
+			redactedKeys[i] = fmt.Sprintf(".%s", key)
+		}
+		return redactedValues, redactedKeys
+	case []interface{}:
+		redactedValues := make([]interface{}, len(value))
+		redactedKeys := []string{}
+		for i, item := range value {
+			redactedItem, itemKeys := redactHelmValue(item)
+			redactedValues[i] = redactedItem
+			for _, itemKey := range itemKeys {
+				redactedKeys = append(redactedKeys, fmt.Sprintf("[%d]%s", i, itemKey))
+			}
+		}
+		return redactedValues, redactedKeys
+	default:
+		return value, nil
+	}
+}
+
+// isEmptyHelmValue returns true if a Helm value has nothing to redact: it's unset, empty, or a flag or a number
+func isEmptyHelmValue(value interface{}) bool {
+	switch value := value.(type) {
+	case string:
+		return len(value) == 0
+	case map[string]interface{}:
+		return len(value) == 0
+	case []interface{}:
+		return len(value) == 0
+	case nil, bool, int, int64, float64:
+		return true
+	}
+	return false
+}
+
+// isSensitiveHelmValueKey returns true if the Helm value with key holds a secret
+func isSensitiveHelmValueKey(key string) bool {
+	if strings.HasSuffix(strings.ToLower(key), secretReferenceHelmValueKey) {
+		return false
+	}
+	for _, sensitiveKey := range sensitiveHelmValueKeys {
+		if strings.Contains(strings.ToLower(key), sensitiveKey) {
+			return true
+		}
+	}
+	return false
+}
+
 // GetDeploymentResources reads the deployment resource file path and sets the Helm resource maps
 func GetDeploymentResources(deploymentResourceFilePath string, valueMapPointer map[string]interface{}, heapMaxMemoryName string) {
 	data, err := ReadFileData(deploymentResourceFilePath)
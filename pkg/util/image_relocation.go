@@ -0,0 +1,229 @@
+/*
+Copyright (C) 2020 Synopsys, Inc.
+
+Licensed to the Apache Software Foundation (ASF) under one
+or more contributor license agreements. See the NOTICE file
+distributed with this work for additional information
+regarding copyright ownership. The ASF licenses this file
+to you under the Apache License, Version 2.0 (the
+"License"); you may not use this file except in compliance
+with the License. You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing,
+software distributed under the License is distributed on an
+"AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+KIND, either express or implied. See the License for the
+specific language governing permissions and limitations
+under the License.
+*/
+
+package util
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/containerd/containerd/content"
+	"github.com/containerd/containerd/content/local"
+	"github.com/containerd/containerd/images"
+	"github.com/containerd/containerd/platforms"
+	"github.com/containerd/containerd/remotes"
+	"github.com/containerd/containerd/remotes/docker"
+	orasdocker "github.com/deislabs/oras/pkg/auth/docker"
+	orascontent "github.com/deislabs/oras/pkg/content"
+	"github.com/docker/distribution/reference"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	log "github.com/sirupsen/logrus"
+)
+
+// OCILayoutPrefix marks an image location as an OCI image layout directory instead of a registry
+const OCILayoutPrefix = "oci:"
+
+// ImageRelocator copies images from their registries (or an OCI image layout) into
+// another registry or an OCI image layout
+type ImageRelocator struct {
+	sourceResolver remotes.Resolver
+	targetResolver remotes.Resolver
+	// sourceLayout is used to find the images instead of their registries if it is set
+	sourceLayout *orascontent.OCIStore
+}
+
+// NewImageRelocator creates an ImageRelocator that authenticates with the credentials in the docker config file.
+// sourceLayout is an OCI image layout directory to copy the images from instead of their registries (optional).
+// plainHTTP and insecureSkipTLSVerify only apply to the target registry
+func NewImageRelocator(sourceLayout string, plainHTTP, insecureSkipTLSVerify bool) (*ImageRelocator, error) {
+	authClient, err := orasdocker.NewClient()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the docker credentials due to %+v", err)
+	}
+	credentials := authClient.(*orasdocker.Client).Credential
+
+	targetClient := http.DefaultClient
+	if insecureSkipTLSVerify {
+		targetClient = &http.Client{
+			Transport: &http.Transport{
+				Proxy:           http.ProxyFromEnvironment,
+				TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+			},
+		}
+	}
+
+	relocator := &ImageRelocator{
+		sourceResolver: docker.NewResolver(docker.ResolverOptions{Credentials: credentials}),
+		targetResolver: docker.NewResolver(docker.ResolverOptions{Credentials: credentials, Client: targetClient, PlainHTTP: plainHTTP}),
+	}
+	if len(sourceLayout) > 0 {
+		if _, err := os.Stat(sourceLayout); err != nil {
+			return nil, fmt.Errorf("unable to find the OCI image layout '%s' due to %s", sourceLayout, err)
+		}
+		if relocator.sourceLayout, err = orascontent.NewOCIStore(sourceLayout); err != nil {
+			return nil, fmt.Errorf("failed to open the OCI image layout '%s' due to %s", sourceLayout, err)
+		}
+	}
+	return relocator, nil
+}
+
+// NormalizeImageName returns the fully qualified name of an image
+// alpine -> docker.io/library/alpine:latest
+func NormalizeImageName(image string) (string, error) {
+	named, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return "", fmt.Errorf("invalid image '%s': %+v", image, err)
+	}
+	return reference.TagNameOnly(named).String(), nil
+}
+
+// RelocatedImageName returns the name of an image after it is copied into registry
+// docker.io/blackducksoftware/blackduck-nginx:1.0.26, registry.local/synopsys -> registry.local/synopsys/blackduck-nginx:1.0.26
+func RelocatedImageName(image, registry string) (string, error) {
+	normalizedImage, err := NormalizeImageName(image)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s/%s:%s", strings.TrimSuffix(registry, "/"), ParseImageName(normalizedImage), ParseImageTag(normalizedImage)), nil
+}
+
+// Relocate copies image into target and returns the new name of the image. target is either a registry
+// (registry.local/synopsys) or an OCI image layout directory prefixed with OCILayoutPrefix (oci:/tmp/images)
+func (r *ImageRelocator) Relocate(ctx context.Context, image, target string) (string, error) {
+	sourceImage, err := NormalizeImageName(image)
+	if err != nil {
+		return "", err
+	}
+
+	// Copy the image into an OCI image layout
+	if strings.HasPrefix(target, OCILayoutPrefix) {
+		layoutDir := strings.TrimPrefix(target, OCILayoutPrefix)
+		if err := os.MkdirAll(layoutDir, 0755); err != nil {
+			return "", fmt.Errorf("failed to create the OCI image layout '%s' due to %s", layoutDir, err)
+		}
+		targetLayout, err := orascontent.NewOCIStore(layoutDir)
+		if err != nil {
+			return "", fmt.Errorf("failed to open the OCI image layout '%s' due to %s", layoutDir, err)
+		}
+		desc, err := r.fetch(ctx, sourceImage, targetLayout)
+		if err != nil {
+			return "", err
+		}
+		targetLayout.AddReference(sourceImage, desc)
+		if err := targetLayout.SaveIndex(); err != nil {
+			return "", fmt.Errorf("failed to save the index of the OCI image layout '%s' due to %s", layoutDir, err)
+		}
+		return sourceImage, nil
+	}
+
+	// Copy the image into a registry
+	targetImage, err := RelocatedImageName(sourceImage, target)
+	if err != nil {
+		return "", err
+	}
+	var store content.Store
+	var desc ocispec.Descriptor
+	if r.sourceLayout != nil {
+		if desc, err = r.resolveFromLayout(sourceImage); err != nil {
+			return "", err
+		}
+		store = r.sourceLayout
+	} else {
+		tmpDir, err := ioutil.TempDir("", "synopsysctl-images")
+		if err != nil {
+			return "", fmt.Errorf("failed to create a temporary directory for '%s' due to %s", sourceImage, err)
+		}
+		defer os.RemoveAll(tmpDir)
+		if store, err = local.NewStore(tmpDir); err != nil {
+			return "", fmt.Errorf("failed to create a temporary store for '%s' due to %s", sourceImage, err)
+		}
+		if desc, err = r.fetch(ctx, sourceImage, store); err != nil {
+			return "", err
+		}
+	}
+	pusher, err := r.targetResolver.Pusher(ctx, targetImage)
+	if err != nil {
+		return "", fmt.Errorf("unable to push to '%s' due to %s", targetImage, err)
+	}
+	if err := remotes.PushContent(ctx, pusher, desc, store, platforms.All, nil); err != nil {
+		return "", fmt.Errorf("failed to push '%s' due to %s", targetImage, err)
+	}
+	log.Debugf("copied '%s' to '%s'", sourceImage, targetImage)
+	return targetImage, nil
+}
+
+// fetch copies the manifests and layers of image for all platforms into store and returns the image's descriptor
+func (r *ImageRelocator) fetch(ctx context.Context, image string, store content.Store) (ocispec.Descriptor, error) {
+	var desc ocispec.Descriptor
+	var fetcher remotes.Fetcher
+	var err error
+	if r.sourceLayout != nil {
+		if desc, err = r.resolveFromLayout(image); err != nil {
+			return desc, err
+		}
+		fetcher = providerFetcher{provider: r.sourceLayout}
+	} else {
+		var name string
+		if name, desc, err = r.sourceResolver.Resolve(ctx, image); err != nil {
+			return desc, fmt.Errorf("failed to find '%s' due to %s", image, err)
+		}
+		if fetcher, err = r.sourceResolver.Fetcher(ctx, name); err != nil {
+			return desc, fmt.Errorf("unable to pull '%s' due to %s", image, err)
+		}
+	}
+	handler := images.Handlers(remotes.FetchHandler(store, fetcher), images.ChildrenHandler(store))
+	if err := images.Dispatch(ctx, handler, nil, desc); err != nil {
+		return desc, fmt.Errorf("failed to pull '%s' due to %s", image, err)
+	}
+	return desc, nil
+}
+
+// resolveFromLayout returns the descriptor of image in the source OCI image layout
+func (r *ImageRelocator) resolveFromLayout(image string) (ocispec.Descriptor, error) {
+	desc, ok := r.sourceLayout.ListReferences()[image]
+	if !ok {
+		return desc, fmt.Errorf("unable to find '%s' in the OCI image layout", image)
+	}
+	return desc, nil
+}
+
+// providerFetcher fetches content from a content.Provider, such as an OCI image layout
+type providerFetcher struct {
+	provider content.Provider
+}
+
+// Fetch returns a reader for the content described by desc
+func (f providerFetcher) Fetch(ctx context.Context, desc ocispec.Descriptor) (io.ReadCloser, error) {
+	readerAt, err := f.provider.ReaderAt(ctx, desc)
+	if err != nil {
+		return nil, err
+	}
+	return struct {
+		io.Reader
+		io.Closer
+	}{content.NewReader(readerAt), readerAt}, nil
+}
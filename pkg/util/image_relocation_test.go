@@ -0,0 +1,181 @@
+/*
+Copyright (C) 2020 Synopsys, Inc.
+
+Licensed to the Apache Software Foundation (ASF) under one
+or more contributor license agreements. See the NOTICE file
+distributed with this work for additional information
+regarding copyright ownership. The ASF licenses this file
+to you under the Apache License, Version 2.0 (the
+"License"); you may not use this file except in compliance
+with the License. You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing,
+software distributed under the License is distributed on an
+"AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+KIND, either express or implied. See the License for the
+specific language governing permissions and limitations
+under the License.
+*/
+
+package util
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/containerd/containerd/content"
+	orascontent "github.com/deislabs/oras/pkg/content"
+	"github.com/docker/distribution/configuration"
+	"github.com/docker/distribution/registry/handlers"
+	_ "github.com/docker/distribution/registry/storage/driver/inmemory"
+	digest "github.com/opencontainers/go-digest"
+	specs "github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRelocatedImageName(t *testing.T) {
+	type test struct {
+		testDesc string
+		image    string
+		registry string
+		expected string
+	}
+	tests := []test{
+		{
+			testDesc: "image with a registry",
+			image:    "docker.io/blackducksoftware/blackduck-nginx:1.0.26",
+			registry: "registry.local:5000/synopsys",
+			expected: "registry.local:5000/synopsys/blackduck-nginx:1.0.26",
+		},
+		{
+			testDesc: "image without a registry or tag",
+			image:    "alpine",
+			registry: "registry.local:5000/synopsys/",
+			expected: "registry.local:5000/synopsys/alpine:latest",
+		},
+	}
+
+	for _, test := range tests {
+		relocatedImage, err := RelocatedImageName(test.image, test.registry)
+		assert.NoError(t, err, test.testDesc)
+		assert.Equal(t, test.expected, relocatedImage, test.testDesc)
+	}
+}
+
+// writeTestBlob adds data to store and returns its descriptor
+func writeTestBlob(t *testing.T, store content.Store, mediaType string, data []byte) ocispec.Descriptor {
+	desc := ocispec.Descriptor{MediaType: mediaType, Digest: digest.FromBytes(data), Size: int64(len(data))}
+	if err := content.WriteBlob(context.Background(), store, desc.Digest.String(), bytes.NewReader(data), desc); err != nil {
+		t.Fatal(err)
+	}
+	return desc
+}
+
+// createTestOCILayout creates an OCI image layout in dir with one image and returns the
+// descriptors of its manifest, config and layer
+func createTestOCILayout(t *testing.T, dir, image string) []ocispec.Descriptor {
+	sourceLayout, err := orascontent.NewOCIStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	configDesc := writeTestBlob(t, sourceLayout, ocispec.MediaTypeImageConfig, []byte("{}"))
+	layerDesc := writeTestBlob(t, sourceLayout, ocispec.MediaTypeImageLayerGzip, []byte("layer"))
+	manifest, err := json.Marshal(ocispec.Manifest{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		Config:    configDesc,
+		Layers:    []ocispec.Descriptor{layerDesc},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	manifestDesc := writeTestBlob(t, sourceLayout, ocispec.MediaTypeImageManifest, manifest)
+	sourceLayout.AddReference(image, manifestDesc)
+	if err := sourceLayout.SaveIndex(); err != nil {
+		t.Fatal(err)
+	}
+	return []ocispec.Descriptor{manifestDesc, configDesc, layerDesc}
+}
+
+func TestRelocateBetweenOCILayouts(t *testing.T) {
+	dir, err := ioutil.TempDir("", "image-relocation")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	sourceDir := filepath.Join(dir, "source")
+	image := "docker.io/blackducksoftware/blackduck-nginx:1.0.26"
+	descs := createTestOCILayout(t, sourceDir, image)
+
+	relocator, err := NewImageRelocator(sourceDir, false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	targetDir := filepath.Join(dir, "target")
+	relocatedImage, err := relocator.Relocate(context.Background(), image, OCILayoutPrefix+targetDir)
+	assert.NoError(t, err)
+	assert.Equal(t, image, relocatedImage)
+
+	targetLayout, err := orascontent.NewOCIStore(targetDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, descs[0].Digest, targetLayout.ListReferences()[image].Digest)
+	for _, desc := range descs {
+		_, err := targetLayout.Info(context.Background(), desc.Digest)
+		assert.NoError(t, err, "expected '%s' to be copied", desc.MediaType)
+	}
+
+	_, err = relocator.Relocate(context.Background(), "docker.io/blackducksoftware/blackduck-webapp:2020.4.0", OCILayoutPrefix+targetDir)
+	assert.Error(t, err, "images that aren't in the source layout can't be copied")
+}
+
+func TestRelocateToRegistry(t *testing.T) {
+	dir, err := ioutil.TempDir("", "image-relocation")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// Start a local registry
+	config := &configuration.Configuration{}
+	config.Storage = map[string]configuration.Parameters{"inmemory": map[string]interface{}{}}
+	registry := httptest.NewServer(handlers.NewApp(context.Background(), config))
+	defer registry.Close()
+	registryHost := strings.TrimPrefix(registry.URL, "http://")
+
+	image := "docker.io/blackducksoftware/blackduck-nginx:1.0.26"
+	descs := createTestOCILayout(t, dir, image)
+
+	relocator, err := NewImageRelocator(dir, true, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	relocatedImage, err := relocator.Relocate(context.Background(), image, registryHost+"/synopsys")
+	assert.NoError(t, err)
+	assert.Equal(t, registryHost+"/synopsys/blackduck-nginx:1.0.26", relocatedImage)
+
+	request, err := http.NewRequest(http.MethodHead, registry.URL+"/v2/synopsys/blackduck-nginx/manifests/1.0.26", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	request.Header.Set("Accept", ocispec.MediaTypeImageManifest)
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	response.Body.Close()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, descs[0].Digest.String(), response.Header.Get("Docker-Content-Digest"))
+}
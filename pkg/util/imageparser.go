@@ -23,6 +23,8 @@ package util
 
 import (
 	"regexp"
+	"sort"
+	"strings"
 )
 
 // ValidateFullImageString takes a docker image string and
@@ -86,3 +88,24 @@ func ParseImageRepo(image string) string {
 	}
 	return repoSubstringSubmatch[1]
 }
+
+// ParseImagesFromManifests takes the kube manifest files of an application and
+// returns the sorted, unique list of images referenced by its containers
+// manifests := "containers:\n- image: docker.io/blackducksoftware/blackduck-nginx:1.0.26"
+// images = [docker.io/blackducksoftware/blackduck-nginx:1.0.26]
+func ParseImagesFromManifests(manifests string) []string {
+	imageRegexp := regexp.MustCompile(`(?m)^[\s-]*image:\s*["']?([^"'\s]+)["']?\s*$`)
+	imageSet := map[string]bool{}
+	for _, subMatch := range imageRegexp.FindAllStringSubmatch(manifests, -1) {
+		image := strings.TrimSpace(subMatch[1])
+		if len(image) > 0 {
+			imageSet[image] = true
+		}
+	}
+	images := []string{}
+	for image := range imageSet {
+		images = append(images, image)
+	}
+	sort.Strings(images)
+	return images
+}
@@ -22,6 +22,7 @@ under the License.
 package util
 
 import (
+	"reflect"
 	"testing"
 )
 
@@ -288,3 +289,74 @@ func TestParseImageRepo(t *testing.T) {
 		})
 	}
 }
+
+func TestParseImagesFromManifests(t *testing.T) {
+	type args struct {
+		manifests string
+	}
+	tests := []struct {
+		name string
+		args args
+		want []string
+	}{
+		{
+			name: "images in deployments and init containers",
+			args: args{
+				manifests: `---
+# Source: blackduck/templates/webserver.yaml
+apiVersion: apps/v1
+kind: Deployment
+spec:
+  template:
+    spec:
+      initContainers:
+      - name: synopsys-init
+        image: docker.io/blackducksoftware/synopsys-init:1.0.0
+      containers:
+      - env:
+        - name: HUB_WEBSERVER_PORT
+          value: "8443"
+        image: "docker.io/blackducksoftware/blackduck-nginx:1.0.26"
+        name: webserver
+---
+# Source: blackduck/templates/postgres.yaml
+apiVersion: apps/v1
+kind: Deployment
+spec:
+  template:
+    spec:
+      containers:
+        - image: 'docker.io/centos/postgresql-96-centos7:9.6'
+          name: postgres
+`,
+			},
+			want: []string{
+				"docker.io/blackducksoftware/blackduck-nginx:1.0.26",
+				"docker.io/blackducksoftware/synopsys-init:1.0.0",
+				"docker.io/centos/postgresql-96-centos7:9.6",
+			},
+		},
+		{
+			name: "duplicate images",
+			args: args{
+				manifests: "containers:\n- image: alpine:3.11\n- image: alpine:3.11\n",
+			},
+			want: []string{"alpine:3.11"},
+		},
+		{
+			name: "no images",
+			args: args{
+				manifests: "kind: Service\nspec:\n  type: ClusterIP\n",
+			},
+			want: []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseImagesFromManifests(tt.args.manifests)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseImagesFromManifests() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+/*
+Copyright (C) 2020 Synopsys, Inc.
+
+Licensed to the Apache Software Foundation (ASF) under one
+or more contributor license agreements. See the NOTICE file
+distributed with this work for additional information
+regarding copyright ownership. The ASF licenses this file
+to you under the Apache License, Version 2.0 (the
+"License"); you may not use this file except in compliance
+with the License. You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing,
+software distributed under the License is distributed on an
+"AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+KIND, either express or implied. See the License for the
+specific language governing permissions and limitations
+under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// PodSummary is the readiness of a pod of an instance
+type PodSummary struct {
+	Name      string `json:"name"`
+	Component string `json:"component,omitempty"`
+	Ready     string `json:"ready"`
+	Status    string `json:"status"`
+	Restarts  int32  `json:"restarts"`
+	Node      string `json:"node,omitempty"`
+}
+
+// PVCSummary is the binding and capacity of a persistent volume claim of an instance
+type PVCSummary struct {
+	Name         string `json:"name"`
+	Status       string `json:"status"`
+	Capacity     string `json:"capacity,omitempty"`
+	StorageClass string `json:"storageClass,omitempty"`
+}
+
+// GetPodSummary returns the number of ready containers, the status and the restarts of a pod, where the status
+// is the reason that a container is waiting or terminated if there is one (e.g. CrashLoopBackOff)
+func GetPodSummary(pod corev1.Pod) PodSummary {
+	summary := PodSummary{Name: pod.Name, Component: pod.Labels["component"], Status: string(pod.Status.Phase), Node: pod.Spec.NodeName}
+	readyContainers := 0
+	for _, container := range pod.Status.ContainerStatuses {
+		summary.Restarts += container.RestartCount
+		if container.Ready {
+			readyContainers++
+		} else if container.State.Waiting != nil && len(container.State.Waiting.Reason) > 0 {
+			summary.Status = container.State.Waiting.Reason
+		} else if container.State.Terminated != nil && len(container.State.Terminated.Reason) > 0 {
+			summary.Status = container.State.Terminated.Reason
+		}
+	}
+	if pod.DeletionTimestamp != nil {
+		summary.Status = "Terminating"
+	}
+	summary.Ready = fmt.Sprintf("%d/%d", readyContainers, len(pod.Spec.Containers))
+	return summary
+}
+
+// GetPVCSummary returns the status, the capacity and the storage class of a persistent volume claim, the
+// capacity is the requested storage until the claim is bound
+func GetPVCSummary(pvc corev1.PersistentVolumeClaim) PVCSummary {
+	summary := PVCSummary{Name: pvc.Name, Status: string(pvc.Status.Phase)}
+	if capacity, ok := pvc.Status.Capacity[corev1.ResourceStorage]; ok {
+		summary.Capacity = capacity.String()
+	} else if request, ok := pvc.Spec.Resources.Requests[corev1.ResourceStorage]; ok {
+		summary.Capacity = request.String()
+	}
+	if pvc.Spec.StorageClassName != nil {
+		summary.StorageClass = *pvc.Spec.StorageClassName
+	}
+	return summary
+}
@@ -0,0 +1,68 @@
+/*
+Copyright (C) 2020 Synopsys, Inc.
+
+Licensed to the Apache Software Foundation (ASF) under one
+or more contributor license agreements. See the NOTICE file
+distributed with this work for additional information
+regarding copyright ownership. The ASF licenses this file
+to you under the Apache License, Version 2.0 (the
+"License"); you may not use this file except in compliance
+with the License. You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing,
+software distributed under the License is distributed on an
+"AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+KIND, either express or implied. See the License for the
+specific language governing permissions and limitations
+under the License.
+*/
+
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetPodSummary(t *testing.T) {
+	pod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "bd-blackduck-webserver-5d8f", Labels: map[string]string{"component": "webserver"}},
+		Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "webserver"}, {Name: "sidecar"}}},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{
+				{Name: "webserver", RestartCount: 3, State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}}},
+				{Name: "sidecar", RestartCount: 1, Ready: true},
+			},
+		},
+	}
+	assert.Equal(t, PodSummary{Name: "bd-blackduck-webserver-5d8f", Component: "webserver", Ready: "1/2", Status: "CrashLoopBackOff", Restarts: 4}, GetPodSummary(pod))
+
+	pod.Status.ContainerStatuses[0] = corev1.ContainerStatus{Name: "webserver", RestartCount: 3, Ready: true}
+	assert.Equal(t, PodSummary{Name: "bd-blackduck-webserver-5d8f", Component: "webserver", Ready: "2/2", Status: "Running", Restarts: 4}, GetPodSummary(pod))
+
+	pod.Spec.NodeName = "node-1"
+	assert.Equal(t, "node-1", GetPodSummary(pod).Node)
+}
+
+func TestGetPVCSummary(t *testing.T) {
+	storageClass := "standard"
+	pvc := corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "bd-blackduck-postgres"},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			StorageClassName: &storageClass,
+			Resources:        corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("150Gi")}},
+		},
+		Status: corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimPending},
+	}
+	assert.Equal(t, PVCSummary{Name: "bd-blackduck-postgres", Status: "Pending", Capacity: "150Gi", StorageClass: "standard"}, GetPVCSummary(pvc))
+
+	pvc.Status = corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimBound, Capacity: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("200Gi")}}
+	assert.Equal(t, PVCSummary{Name: "bd-blackduck-postgres", Status: "Bound", Capacity: "200Gi", StorageClass: "standard"}, GetPVCSummary(pvc))
+}
@@ -0,0 +1,175 @@
+/*
+Copyright (C) 2020 Synopsys, Inc.
+
+Licensed to the Apache Software Foundation (ASF) under one
+or more contributor license agreements. See the NOTICE file
+distributed with this work for additional information
+regarding copyright ownership. The ASF licenses this file
+to you under the Apache License, Version 2.0 (the
+"License"); you may not use this file except in compliance
+with the License. You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing,
+software distributed under the License is distributed on an
+"AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+KIND, either express or implied. See the License for the
+specific language governing permissions and limitations
+under the License.
+*/
+
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+
+	"github.com/ghodss/yaml"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+// Output formats of the commands that print instances and their resources
+const (
+	OutputTable             = "table"
+	OutputWide              = "wide"
+	OutputJSON              = "json"
+	OutputYAML              = "yaml"
+	OutputJSONPathPrefix    = "jsonpath="
+	OutputGoTemplatePrefix  = "go-template="
+	OutputFormatDescription = "table|wide|json|yaml|jsonpath=TEMPLATE|go-template=TEMPLATE"
+)
+
+// TableColumn is a column of a table, the wide columns are only printed in the wide output format
+type TableColumn struct {
+	Header string
+	Wide   bool
+}
+
+// Table is printed in the table and wide output formats, the empty cells are printed as -
+type Table struct {
+	Columns []TableColumn
+	Rows    [][]string
+}
+
+// ValidateOutputFormat returns an error if the output format isn't supported
+func ValidateOutputFormat(format string) error {
+	switch {
+	case format == OutputTable, format == OutputWide, format == OutputJSON, format == OutputYAML:
+		return nil
+	case strings.HasPrefix(format, OutputJSONPathPrefix) && len(format) > len(OutputJSONPathPrefix):
+		return nil
+	case strings.HasPrefix(format, OutputGoTemplatePrefix) && len(format) > len(OutputGoTemplatePrefix):
+		return nil
+	}
+	return fmt.Errorf("'%s' is an invalid output format, must be one of %s", format, OutputFormatDescription)
+}
+
+// PrintOutput prints obj in the json, yaml, jsonpath and go-template output formats, with the JSON field names
+// as keys, and the tables in the table and wide output formats
+func PrintOutput(w io.Writer, format string, obj interface{}, tables ...Table) error {
+	if err := ValidateOutputFormat(format); err != nil {
+		return err
+	}
+	switch {
+	case format == OutputTable, format == OutputWide:
+		if len(tables) == 0 {
+			return fmt.Errorf("the %s output format isn't supported by this command, use json or yaml", format)
+		}
+		return printTables(w, format == OutputWide, tables)
+	case format == OutputJSON:
+		b, err := json.MarshalIndent(obj, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to convert the output to JSON due to %+v", err)
+		}
+		_, err = fmt.Fprintln(w, string(b))
+		return err
+	case format == OutputYAML:
+		b, err := yaml.Marshal(obj)
+		if err != nil {
+			return fmt.Errorf("failed to convert the output to YAML due to %+v", err)
+		}
+		_, err = fmt.Fprint(w, string(b))
+		return err
+	}
+
+	// The templates are executed on the JSON form of obj so that they use the same keys as the json output
+	b, err := json.Marshal(obj)
+	if err != nil {
+		return fmt.Errorf("failed to convert the output to JSON due to %+v", err)
+	}
+	var data interface{}
+	if err := json.Unmarshal(b, &data); err != nil {
+		return fmt.Errorf("failed to convert the output to JSON due to %+v", err)
+	}
+	if strings.HasPrefix(format, OutputJSONPathPrefix) {
+		return printJSONPath(w, strings.TrimPrefix(format, OutputJSONPathPrefix), data)
+	}
+	tmpl, err := template.New("output").Parse(strings.TrimPrefix(format, OutputGoTemplatePrefix))
+	if err != nil {
+		return fmt.Errorf("failed to parse the go-template due to %+v", err)
+	}
+	if err := tmpl.Execute(w, data); err != nil {
+		return fmt.Errorf("failed to execute the go-template due to %+v", err)
+	}
+	return nil
+}
+
+// printJSONPath prints the data selected by a JSONPath template (e.g. {.items[*].name} or .items[*].name)
+func printJSONPath(w io.Writer, expression string, data interface{}) error {
+	if !strings.Contains(expression, "{") {
+		expression = fmt.Sprintf("{%s}", expression)
+	}
+	parser := jsonpath.New("output")
+	parser.AllowMissingKeys(true)
+	if err := parser.Parse(expression); err != nil {
+		return fmt.Errorf("failed to parse the jsonpath due to %+v", err)
+	}
+	if err := parser.Execute(w, data); err != nil {
+		return fmt.Errorf("failed to execute the jsonpath due to %+v", err)
+	}
+	return nil
+}
+
+// printTables prints the tables separated by an empty line, the tables without rows are skipped
+func printTables(w io.Writer, wide bool, tables []Table) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+	printed := 0
+	for _, table := range tables {
+		if len(table.Rows) == 0 && printed > 0 {
+			continue
+		}
+		if printed > 0 {
+			fmt.Fprintln(tw)
+		}
+		fmt.Fprintln(tw, strings.Join(getTableCells(wide, table.Columns, nil), "\t"))
+		for _, row := range table.Rows {
+			fmt.Fprintln(tw, strings.Join(getTableCells(wide, table.Columns, row), "\t"))
+		}
+		printed++
+	}
+	return tw.Flush()
+}
+
+// getTableCells returns the cells of a row in the columns that are printed, or the headers if row is nil
+func getTableCells(wide bool, columns []TableColumn, row []string) []string {
+	cells := []string{}
+	for i, column := range columns {
+		if column.Wide && !wide {
+			continue
+		}
+		switch {
+		case row == nil:
+			cells = append(cells, column.Header)
+		case i < len(row) && len(row[i]) > 0:
+			cells = append(cells, row[i])
+		default:
+			cells = append(cells, "-")
+		}
+	}
+	return cells
+}
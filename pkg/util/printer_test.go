@@ -0,0 +1,79 @@
+/*
+Copyright (C) 2020 Synopsys, Inc.
+
+Licensed to the Apache Software Foundation (ASF) under one
+or more contributor license agreements. See the NOTICE file
+distributed with this work for additional information
+regarding copyright ownership. The ASF licenses this file
+to you under the Apache License, Version 2.0 (the
+"License"); you may not use this file except in compliance
+with the License. You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing,
+software distributed under the License is distributed on an
+"AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+KIND, either express or implied. See the License for the
+specific language governing permissions and limitations
+under the License.
+*/
+
+package util
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testPrinterItem struct {
+	Name     string `json:"name"`
+	Replicas int    `json:"replicas"`
+}
+
+func TestValidateOutputFormat(t *testing.T) {
+	for _, format := range []string{"table", "wide", "json", "yaml", "jsonpath={.name}", "go-template={{.name}}"} {
+		assert.Nil(t, ValidateOutputFormat(format), format)
+	}
+	for _, format := range []string{"", "xml", "jsonpath=", "go-template="} {
+		assert.NotNil(t, ValidateOutputFormat(format), format)
+	}
+}
+
+func TestPrintOutput(t *testing.T) {
+	items := []testPrinterItem{{Name: "webapp", Replicas: 1}, {Name: "jobrunner", Replicas: 3}}
+	tables := []Table{
+		{
+			Columns: []TableColumn{{Header: "NAME"}, {Header: "REPLICAS"}, {Header: "NOTE", Wide: true}},
+			Rows:    [][]string{{"webapp", "1", ""}, {"jobrunner", "3", "scaled"}},
+		},
+		{
+			Columns: []TableColumn{{Header: "EMPTY"}},
+		},
+	}
+
+	var tests = []struct {
+		format   string
+		expected string
+	}{
+		{format: "table", expected: "NAME        REPLICAS\nwebapp      1\njobrunner   3\n"},
+		{format: "wide", expected: "NAME        REPLICAS   NOTE\nwebapp      1          -\njobrunner   3          scaled\n"},
+		{format: "json", expected: "[\n  {\n    \"name\": \"webapp\",\n    \"replicas\": 1\n  },\n  {\n    \"name\": \"jobrunner\",\n    \"replicas\": 3\n  }\n]\n"},
+		{format: "yaml", expected: "- name: webapp\n  replicas: 1\n- name: jobrunner\n  replicas: 3\n"},
+		{format: "jsonpath={[*].name}", expected: "webapp jobrunner"},
+		{format: "jsonpath=[1].replicas", expected: "3"},
+		{format: "go-template={{range .}}{{.name}}={{.replicas}}\n{{end}}", expected: "webapp=1\njobrunner=3\n"},
+	}
+
+	for _, test := range tests {
+		var output bytes.Buffer
+		assert.Nil(t, PrintOutput(&output, test.format, items, tables...), test.format)
+		assert.Equal(t, test.expected, output.String(), test.format)
+	}
+
+	var output bytes.Buffer
+	assert.NotNil(t, PrintOutput(&output, "table", items))
+	assert.NotNil(t, PrintOutput(&output, "go-template={{.name", items))
+}
@@ -0,0 +1,307 @@
+/*
+Copyright (C) 2020 Synopsys, Inc.
+
+Licensed to the Apache Software Foundation (ASF) under one
+or more contributor license agreements. See the NOTICE file
+distributed with this work for additional information
+regarding copyright ownership. The ASF licenses this file
+to you under the Apache License, Version 2.0 (the
+"License"); you may not use this file except in compliance
+with the License. You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing,
+software distributed under the License is distributed on an
+"AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+KIND, either express or implied. See the License for the
+specific language governing permissions and limitations
+under the License.
+*/
+
+package util
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/pmezard/go-difflib/difflib"
+	"helm.sh/helm/v3/pkg/releaseutil"
+)
+
+// ANSI escape codes used to colorize diffs
+const (
+	diffColorReset  = "\x1b[0m"
+	diffColorBold   = "\x1b[1m"
+	diffColorRed    = "\x1b[31m"
+	diffColorGreen  = "\x1b[32m"
+	diffColorYellow = "\x1b[33m"
+	diffColorCyan   = "\x1b[36m"
+)
+
+// redactedHelmValue is shown in place of the Helm values that hold secrets
+const redactedHelmValue = "<redacted>"
+
+// changedSecretValue is shown in place of the data of a secret that changes
+const changedSecretValue = "<changed>"
+
+// DiffWithHelm3 returns the changes to the Helm values and to the resources that UpdateWithHelm3 would
+// make to the release, without applying them
+func DiffWithHelm3(releaseName, namespace, chartURL string, vals map[string]interface{}, kubeConfig string, color bool, extraFiles ...string) (string, error) {
+	actionConfig, err := CreateHelmActionConfiguration(kubeConfig, "", namespace)
+	if err != nil {
+		return "", err
+	}
+	liveRelease, err := GetWithHelm3(releaseName, namespace, kubeConfig)
+	if err != nil {
+		return "", err
+	}
+
+	chart, err := LoadChart(chartURL, actionConfig)
+	if err != nil {
+		return "", fmt.Errorf("failed to load release at '%s' for updating: %s", chartURL, err)
+	}
+	validInstallableChart, err := isChartInstallable(chart)
+	if !validInstallableChart {
+		return "", fmt.Errorf("release at '%s' is not installable: %s", chartURL, err)
+	}
+	if err := mergeValuesWithExtraFilesFromChart(chart, vals, extraFiles); err != nil {
+		return "", fmt.Errorf("failed to merge extra configuration files during update due to %s", err)
+	}
+
+	// The manifest of a release doesn't include the CRDs, so they are left out of the proposed release as well
+	_, proposedRelease, err := renderRelease(releaseName, namespace, chart, vals, actionConfig, false)
+	if err != nil {
+		return "", fmt.Errorf("failed to render the resources of release '%s': %s", releaseName, err)
+	}
+
+	var output bytes.Buffer
+	fmt.Fprintln(&output, "Helm values:")
+	valueChanges := DiffHelmValues(liveRelease.Config, vals)
+	if len(valueChanges) == 0 {
+		fmt.Fprintln(&output, "  No changes to the Helm values")
+	}
+	for _, valueChange := range valueChanges {
+		if color {
+			valueChange = colorizeDiffLine(valueChange)
+		}
+		fmt.Fprintf(&output, "  %s\n", valueChange)
+	}
+	fmt.Fprintln(&output)
+	fmt.Fprintln(&output, "Resources:")
+	fmt.Fprint(&output, DiffManifests(liveRelease.Manifest, proposedRelease.Manifest, color))
+	return output.String(), nil
+}
+
+// DiffManifests returns a unified diff of each resource that is added, changed or removed between the
+// old and the new manifests, followed by a summary of the changes
+func DiffManifests(oldManifests, newManifests string, color bool) string {
+	oldResources := splitManifestsByResource(oldManifests)
+	newResources := splitManifestsByResource(newManifests)
+
+	resourceNames := []string{}
+	for name := range oldResources {
+		resourceNames = append(resourceNames, name)
+	}
+	for name := range newResources {
+		if _, ok := oldResources[name]; !ok {
+			resourceNames = append(resourceNames, name)
+		}
+	}
+	sort.Strings(resourceNames)
+
+	var output bytes.Buffer
+	added, changed, removed := 0, 0, 0
+	for _, name := range resourceNames {
+		oldResource, inOld := oldResources[name]
+		newResource, inNew := newResources[name]
+		switch {
+		case !inOld:
+			added++
+		case !inNew:
+			removed++
+		case oldResource != newResource:
+			changed++
+		default:
+			continue
+		}
+		// The data of secrets is compared, but not shown
+		if strings.HasPrefix(name, "Secret/") {
+			oldResource, newResource = maskSecretData(oldResource, newResource)
+		}
+		diff, _ := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        difflib.SplitLines(oldResource),
+			B:        difflib.SplitLines(newResource),
+			FromFile: fmt.Sprintf("live/%s", name),
+			ToFile:   fmt.Sprintf("proposed/%s", name),
+			Context:  3,
+		})
+		for _, line := range difflib.SplitLines(diff) {
+			if color {
+				line = colorizeDiffLine(strings.TrimSuffix(line, "\n")) + "\n"
+			}
+			fmt.Fprint(&output, line)
+		}
+	}
+	if added+changed+removed == 0 {
+		fmt.Fprintln(&output, "No changes to the resources")
+		return output.String()
+	}
+	fmt.Fprintf(&output, "\n%d resource(s) to add, %d to change, %d to remove\n", added, changed, removed)
+	return output.String()
+}
+
+// DiffHelmValues returns the Helm values that are added (+), changed (~) or removed (-) between the old and
+// the new values, sorted by key (e.g. ~ postgres.host: "old" -> "new"). The values that hold secrets are redacted
+func DiffHelmValues(oldValues, newValues map[string]interface{}) []string {
+	oldFlatValues := map[string]string{}
+	flattenHelmValues("", oldValues, oldFlatValues)
+	newFlatValues := map[string]string{}
+	flattenHelmValues("", newValues, newFlatValues)
+
+	changes := []string{}
+	for _, key := range changedFlatHelmValues(oldFlatValues, newFlatValues) {
+		oldValue, inOld := oldFlatValues[key]
+		newValue, inNew := newFlatValues[key]
+		// Secrets are compared, but not shown
+		for _, keyPart := range strings.Split(key, ".") {
+			if isSensitiveHelmValueKey(keyPart) {
+				oldValue, newValue = redactedHelmValue, redactedHelmValue
+			}
+		}
+		switch {
+		case !inOld:
+			changes = append(changes, fmt.Sprintf("+ %s: %s", key, newValue))
+		case !inNew:
+			changes = append(changes, fmt.Sprintf("- %s: %s", key, oldValue))
+		default:
+			changes = append(changes, fmt.Sprintf("~ %s: %s -> %s", key, oldValue, newValue))
+		}
+	}
+	return changes
+}
+
+// ChangedHelmValues returns the sorted keys of the Helm values that are added, changed or removed between
+// the old and the new values (e.g. postgres.host)
+func ChangedHelmValues(oldValues, newValues map[string]interface{}) []string {
+	oldFlatValues := map[string]string{}
+	flattenHelmValues("", oldValues, oldFlatValues)
+	newFlatValues := map[string]string{}
+	flattenHelmValues("", newValues, newFlatValues)
+	return changedFlatHelmValues(oldFlatValues, newFlatValues)
+}
+
+// changedFlatHelmValues returns the sorted keys of the flattened values that differ
+func changedFlatHelmValues(oldFlatValues, newFlatValues map[string]string) []string {
+	keys := []string{}
+	for key, oldValue := range oldFlatValues {
+		if newValue, ok := newFlatValues[key]; !ok || newValue != oldValue {
+			keys = append(keys, key)
+		}
+	}
+	for key := range newFlatValues {
+		if _, ok := oldFlatValues[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// flattenHelmValues adds the leaves of values to flatValues with their dotted keys and their values as JSON
+func flattenHelmValues(prefix string, values map[string]interface{}, flatValues map[string]string) {
+	for key, value := range values {
+		if len(prefix) > 0 {
+			key = fmt.Sprintf("%s.%s", prefix, key)
+		}
+		if nestedValues, ok := value.(map[string]interface{}); ok && len(nestedValues) > 0 {
+			flattenHelmValues(key, nestedValues, flatValues)
+			continue
+		}
+		// JSON makes the numbers from the release (float64) and from the flags (int) comparable
+		jsonValue, err := json.Marshal(value)
+		if err != nil {
+			jsonValue = []byte(fmt.Sprintf("%v", value))
+		}
+		flatValues[key] = string(jsonValue)
+	}
+}
+
+// splitManifestsByResource returns the documents of a manifest by their kind and name (e.g. Deployment/webserver)
+func splitManifestsByResource(manifests string) map[string]string {
+	resources := map[string]string{}
+	for _, manifest := range releaseutil.SplitManifests(manifests) {
+		var head releaseutil.SimpleHead
+		if err := yaml.Unmarshal([]byte(manifest), &head); err != nil || len(head.Kind) == 0 {
+			continue
+		}
+		name := head.Kind
+		if head.Metadata != nil {
+			name = fmt.Sprintf("%s/%s", head.Kind, head.Metadata.Name)
+		}
+		resources[name] = strings.TrimSpace(manifest) + "\n"
+	}
+	return resources
+}
+
+// maskSecretData returns the manifests of a secret with the values of its data and stringData replaced by
+// redactedHelmValue, or by changedSecretValue in the new manifest for the values that change
+func maskSecretData(oldManifest, newManifest string) (string, string) {
+	oldSecret := map[string]interface{}{}
+	newSecret := map[string]interface{}{}
+	if len(oldManifest) > 0 {
+		if err := yaml.Unmarshal([]byte(oldManifest), &oldSecret); err != nil {
+			oldManifest = redactedHelmValue + "\n"
+		}
+	}
+	if len(newManifest) > 0 {
+		if err := yaml.Unmarshal([]byte(newManifest), &newSecret); err != nil {
+			newManifest = redactedHelmValue + "\n"
+		}
+	}
+	for _, field := range []string{"data", "stringData"} {
+		oldData, _ := oldSecret[field].(map[string]interface{})
+		newData, _ := newSecret[field].(map[string]interface{})
+		for key, oldValue := range oldData {
+			if newValue, ok := newData[key]; ok && fmt.Sprintf("%v", newValue) != fmt.Sprintf("%v", oldValue) {
+				newData[key] = changedSecretValue
+			} else if ok {
+				newData[key] = redactedHelmValue
+			}
+			oldData[key] = redactedHelmValue
+		}
+		for key := range newData {
+			if _, ok := oldData[key]; !ok {
+				newData[key] = redactedHelmValue
+			}
+		}
+	}
+	if oldYAML, err := yaml.Marshal(oldSecret); err == nil && len(oldSecret) > 0 {
+		oldManifest = string(oldYAML)
+	}
+	if newYAML, err := yaml.Marshal(newSecret); err == nil && len(newSecret) > 0 {
+		newManifest = string(newYAML)
+	}
+	return oldManifest, newManifest
+}
+
+// colorizeDiffLine colors a line of a diff by its prefix
+func colorizeDiffLine(line string) string {
+	switch {
+	case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+		return diffColorBold + line + diffColorReset
+	case strings.HasPrefix(line, "@@"):
+		return diffColorCyan + line + diffColorReset
+	case strings.HasPrefix(line, "+"):
+		return diffColorGreen + line + diffColorReset
+	case strings.HasPrefix(line, "-"):
+		return diffColorRed + line + diffColorReset
+	case strings.HasPrefix(line, "~"):
+		return diffColorYellow + line + diffColorReset
+	}
+	return line
+}
@@ -0,0 +1,175 @@
+/*
+Copyright (C) 2020 Synopsys, Inc.
+
+Licensed to the Apache Software Foundation (ASF) under one
+or more contributor license agreements. See the NOTICE file
+distributed with this work for additional information
+regarding copyright ownership. The ASF licenses this file
+to you under the Apache License, Version 2.0 (the
+"License"); you may not use this file except in compliance
+with the License. You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing,
+software distributed under the License is distributed on an
+"AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+KIND, either express or implied. See the License for the
+specific language governing permissions and limitations
+under the License.
+*/
+
+package util
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testLiveManifest = `---
+# Source: blackduck/templates/webserver.yaml
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: bd-blackduck-webserver
+spec:
+  replicas: 1
+---
+# Source: blackduck/templates/webserver.yaml
+apiVersion: v1
+kind: Service
+metadata:
+  name: bd-blackduck-webserver
+spec:
+  type: ClusterIP
+---
+# Source: blackduck/templates/rabbitmq.yaml
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: bd-blackduck-rabbitmq
+data:
+  key: value
+`
+
+const testProposedManifest = `---
+# Source: blackduck/templates/webserver.yaml
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: bd-blackduck-webserver
+spec:
+  replicas: 2
+---
+# Source: blackduck/templates/webserver.yaml
+apiVersion: v1
+kind: Service
+metadata:
+  name: bd-blackduck-webserver
+spec:
+  type: ClusterIP
+---
+# Source: blackduck/templates/webserver-exposed.yaml
+apiVersion: v1
+kind: Service
+metadata:
+  name: bd-blackduck-webserver-exposed
+spec:
+  type: LoadBalancer
+`
+
+func TestDiffManifests(t *testing.T) {
+	diff := DiffManifests(testLiveManifest, testProposedManifest, false)
+	assert.Contains(t, diff, "--- live/Deployment/bd-blackduck-webserver\n+++ proposed/Deployment/bd-blackduck-webserver\n")
+	assert.Contains(t, diff, "-  replicas: 1\n+  replicas: 2\n")
+	assert.Contains(t, diff, "+++ proposed/Service/bd-blackduck-webserver-exposed\n")
+	assert.Contains(t, diff, "--- live/ConfigMap/bd-blackduck-rabbitmq\n")
+	assert.NotContains(t, diff, "proposed/Service/bd-blackduck-webserver\n", "unchanged resources shouldn't be shown")
+	assert.True(t, strings.HasSuffix(diff, "1 resource(s) to add, 1 to change, 1 to remove\n"))
+	assert.NotContains(t, diff, diffColorReset)
+
+	coloredDiff := DiffManifests(testLiveManifest, testProposedManifest, true)
+	assert.Contains(t, coloredDiff, diffColorRed+"-  replicas: 1"+diffColorReset)
+	assert.Contains(t, coloredDiff, diffColorGreen+"+  replicas: 2"+diffColorReset)
+
+	assert.Equal(t, "No changes to the resources\n", DiffManifests(testLiveManifest, testLiveManifest, false))
+}
+
+func TestDiffManifestsMasksSecrets(t *testing.T) {
+	liveSecret := `---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: bd-blackduck-db-creds
+data:
+  HUB_POSTGRES_ADMIN_PASSWORD: YmxhY2tkdWNr
+  HUB_POSTGRES_USER_PASSWORD: YmxhY2tkdWNr
+`
+	proposedSecret := `---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: bd-blackduck-db-creds
+data:
+  HUB_POSTGRES_ADMIN_PASSWORD: c3lub3BzeXM=
+  HUB_POSTGRES_USER_PASSWORD: YmxhY2tkdWNr
+stringData:
+  HUB_POSTGRES_HOST: postgres.example.com
+`
+	diff := DiffManifests(liveSecret, proposedSecret, false)
+	assert.Contains(t, diff, "--- live/Secret/bd-blackduck-db-creds\n")
+	assert.Contains(t, diff, "-  HUB_POSTGRES_ADMIN_PASSWORD: <redacted>\n+  HUB_POSTGRES_ADMIN_PASSWORD: <changed>\n")
+	assert.Contains(t, diff, "+  HUB_POSTGRES_HOST: <redacted>\n")
+	assert.NotContains(t, diff, "YmxhY2tkdWNr")
+	assert.NotContains(t, diff, "c3lub3BzeXM=")
+	assert.NotContains(t, diff, "postgres.example.com")
+	assert.True(t, strings.HasSuffix(diff, "0 resource(s) to add, 1 to change, 0 to remove\n"))
+
+	assert.NotContains(t, DiffManifests("", proposedSecret, false), "c3lub3BzeXM=")
+}
+
+func TestDiffHelmValues(t *testing.T) {
+	oldValues := map[string]interface{}{
+		"size":     "small",
+		"exposeui": true,
+		"sealKey":  "abcdefghijklmnopqrstuvwxyz123456",
+		"postgres": map[string]interface{}{
+			"host":          "postgres.example.com",
+			"port":          float64(5432),
+			"adminPassword": "blackduck",
+		},
+		"environs": map[string]interface{}{
+			"HUB_PROXY_HOST": "proxy.example.com",
+		},
+	}
+	newValues := map[string]interface{}{
+		"size":     "medium",
+		"exposeui": true,
+		"sealKey":  "abcdefghijklmnopqrstuvwxyz123456",
+		"postgres": map[string]interface{}{
+			"host":          "postgres.example.com",
+			"port":          5432,
+			"adminPassword": "synopsys",
+		},
+		"environs": map[string]interface{}{
+			"HUB_PROXY_PORT": "3128",
+		},
+	}
+	expectedChanges := []string{
+		`- environs.HUB_PROXY_HOST: "proxy.example.com"`,
+		`+ environs.HUB_PROXY_PORT: "3128"`,
+		`~ postgres.adminPassword: <redacted> -> <redacted>`,
+		`~ size: "small" -> "medium"`,
+	}
+	assert.Equal(t, expectedChanges, DiffHelmValues(oldValues, newValues))
+	assert.Empty(t, DiffHelmValues(oldValues, oldValues))
+	assert.Equal(t, []string{"environs.HUB_PROXY_HOST", "environs.HUB_PROXY_PORT", "postgres.adminPassword", "size"}, ChangedHelmValues(oldValues, newValues))
+	assert.Empty(t, ChangedHelmValues(oldValues, oldValues))
+
+	// the values nested under a key that holds secrets are redacted too
+	oldCertificate := map[string]interface{}{"certificate": map[string]interface{}{"crt": "old"}}
+	newCertificate := map[string]interface{}{"certificate": map[string]interface{}{"crt": "new"}}
+	assert.Equal(t, []string{`~ certificate.crt: <redacted> -> <redacted>`}, DiffHelmValues(oldCertificate, newCertificate))
+}
@@ -0,0 +1,215 @@
+/*
+Copyright (C) 2020 Synopsys, Inc.
+
+Licensed to the Apache Software Foundation (ASF) under one
+or more contributor license agreements. See the NOTICE file
+distributed with this work for additional information
+regarding copyright ownership. The ASF licenses this file
+to you under the Apache License, Version 2.0 (the
+"License"); you may not use this file except in compliance
+with the License. You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing,
+software distributed under the License is distributed on an
+"AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+KIND, either express or implied. See the License for the
+specific language governing permissions and limitations
+under the License.
+*/
+
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/ghodss/yaml"
+	"helm.sh/helm/v3/pkg/releaseutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ReplicasAnnotation holds the replicas of a Deployment or StatefulSet while it is stopped
+const ReplicasAnnotation = "synopsys.com/replicas"
+
+// ReleaseWorkloads are the names of the Deployments and StatefulSets of a release
+type ReleaseWorkloads struct {
+	Deployments  []string
+	StatefulSets []string
+}
+
+// GetReleaseWorkloads returns the Deployments and StatefulSets in the manifest of a release
+func GetReleaseWorkloads(manifest string) ReleaseWorkloads {
+	workloads := ReleaseWorkloads{Deployments: []string{}, StatefulSets: []string{}}
+	for _, resource := range releaseutil.SplitManifests(manifest) {
+		var head releaseutil.SimpleHead
+		if err := yaml.Unmarshal([]byte(resource), &head); err != nil || head.Metadata == nil {
+			continue
+		}
+		switch head.Kind {
+		case "Deployment":
+			workloads.Deployments = append(workloads.Deployments, head.Metadata.Name)
+		case "StatefulSet":
+			workloads.StatefulSets = append(workloads.StatefulSets, head.Metadata.Name)
+		}
+	}
+	return workloads
+}
+
+// AreReleaseWorkloadsStopped returns true if the release has Deployments or StatefulSets and all of them were
+// stopped by StopReleaseWorkloads
+func AreReleaseWorkloadsStopped(clientset *kubernetes.Clientset, namespace, manifest string) (bool, error) {
+	workloads := GetReleaseWorkloads(manifest)
+	if len(workloads.Deployments)+len(workloads.StatefulSets) == 0 {
+		return false, nil
+	}
+	for _, name := range workloads.Deployments {
+		deployment, err := clientset.AppsV1().Deployments(namespace).Get(name, metav1.GetOptions{})
+		if err != nil {
+			return false, fmt.Errorf("unable to get deployment '%s' in namespace '%s' due to %+v", name, namespace, err)
+		}
+		if !isStopped(deployment.ObjectMeta, deployment.Spec.Replicas) {
+			return false, nil
+		}
+	}
+	for _, name := range workloads.StatefulSets {
+		statefulSet, err := clientset.AppsV1().StatefulSets(namespace).Get(name, metav1.GetOptions{})
+		if err != nil {
+			return false, fmt.Errorf("unable to get statefulset '%s' in namespace '%s' due to %+v", name, namespace, err)
+		}
+		if !isStopped(statefulSet.ObjectMeta, statefulSet.Spec.Replicas) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// StopReleaseWorkloads scales the Deployments and StatefulSets of a release to 0 replicas and records their
+// replicas in an annotation so that StartReleaseWorkloads can restore them
+func StopReleaseWorkloads(clientset *kubernetes.Clientset, namespace, manifest string) error {
+	workloads := GetReleaseWorkloads(manifest)
+	for _, name := range workloads.Deployments {
+		deployment, err := clientset.AppsV1().Deployments(namespace).Get(name, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("unable to get deployment '%s' in namespace '%s' due to %+v", name, namespace, err)
+		}
+		if !stopReplicas(&deployment.ObjectMeta, &deployment.Spec.Replicas) {
+			continue
+		}
+		if _, err := clientset.AppsV1().Deployments(namespace).Patch(name, types.MergePatchType, getReplicasPatch(deployment.ObjectMeta, deployment.Spec.Replicas)); err != nil {
+			return fmt.Errorf("unable to stop deployment '%s' in namespace '%s' due to %+v", name, namespace, err)
+		}
+	}
+	for _, name := range workloads.StatefulSets {
+		statefulSet, err := clientset.AppsV1().StatefulSets(namespace).Get(name, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("unable to get statefulset '%s' in namespace '%s' due to %+v", name, namespace, err)
+		}
+		if !stopReplicas(&statefulSet.ObjectMeta, &statefulSet.Spec.Replicas) {
+			continue
+		}
+		if _, err := clientset.AppsV1().StatefulSets(namespace).Patch(name, types.MergePatchType, getReplicasPatch(statefulSet.ObjectMeta, statefulSet.Spec.Replicas)); err != nil {
+			return fmt.Errorf("unable to stop statefulset '%s' in namespace '%s' due to %+v", name, namespace, err)
+		}
+	}
+	return nil
+}
+
+// StartReleaseWorkloads restores the replicas of the Deployments and StatefulSets of a release that were
+// stopped by StopReleaseWorkloads
+func StartReleaseWorkloads(clientset *kubernetes.Clientset, namespace, manifest string) error {
+	workloads := GetReleaseWorkloads(manifest)
+	for _, name := range workloads.Deployments {
+		deployment, err := clientset.AppsV1().Deployments(namespace).Get(name, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("unable to get deployment '%s' in namespace '%s' due to %+v", name, namespace, err)
+		}
+		started, err := startReplicas(&deployment.ObjectMeta, &deployment.Spec.Replicas)
+		if err != nil {
+			return fmt.Errorf("unable to start deployment '%s' in namespace '%s' due to %+v", name, namespace, err)
+		}
+		if !started {
+			continue
+		}
+		if _, err := clientset.AppsV1().Deployments(namespace).Patch(name, types.MergePatchType, getReplicasPatch(deployment.ObjectMeta, deployment.Spec.Replicas)); err != nil {
+			return fmt.Errorf("unable to start deployment '%s' in namespace '%s' due to %+v", name, namespace, err)
+		}
+	}
+	for _, name := range workloads.StatefulSets {
+		statefulSet, err := clientset.AppsV1().StatefulSets(namespace).Get(name, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("unable to get statefulset '%s' in namespace '%s' due to %+v", name, namespace, err)
+		}
+		started, err := startReplicas(&statefulSet.ObjectMeta, &statefulSet.Spec.Replicas)
+		if err != nil {
+			return fmt.Errorf("unable to start statefulset '%s' in namespace '%s' due to %+v", name, namespace, err)
+		}
+		if !started {
+			continue
+		}
+		if _, err := clientset.AppsV1().StatefulSets(namespace).Patch(name, types.MergePatchType, getReplicasPatch(statefulSet.ObjectMeta, statefulSet.Spec.Replicas)); err != nil {
+			return fmt.Errorf("unable to start statefulset '%s' in namespace '%s' due to %+v", name, namespace, err)
+		}
+	}
+	return nil
+}
+
+// stopReplicas records the replicas in the annotation and sets them to 0. It returns false if the
+// replicas were already recorded by an earlier stop, so that they aren't overwritten with 0. The replicas
+// are recorded again if something else, e.g. a Helm upgrade, scaled the workload up since then
+func stopReplicas(meta *metav1.ObjectMeta, replicas **int32) bool {
+	if isStopped(*meta, *replicas) {
+		return false
+	}
+	// Kubernetes defaults the replicas to 1 when they aren't set
+	currentReplicas := int32(1)
+	if *replicas != nil {
+		currentReplicas = **replicas
+	}
+	meta.Annotations = InitAnnotations(meta.Annotations)
+	meta.Annotations[ReplicasAnnotation] = strconv.Itoa(int(currentReplicas))
+	zero := int32(0)
+	*replicas = &zero
+	return true
+}
+
+// startReplicas restores the replicas from the annotation and removes it. It returns false if there
+// were no recorded replicas
+func startReplicas(meta *metav1.ObjectMeta, replicas **int32) (bool, error) {
+	value, ok := meta.Annotations[ReplicasAnnotation]
+	if !ok {
+		return false, nil
+	}
+	recordedReplicas, err := strconv.ParseInt(value, 10, 32)
+	if err != nil {
+		return false, fmt.Errorf("'%s' is an invalid value for annotation %s", value, ReplicasAnnotation)
+	}
+	restoredReplicas := int32(recordedReplicas)
+	*replicas = &restoredReplicas
+	delete(meta.Annotations, ReplicasAnnotation)
+	return true, nil
+}
+
+// getReplicasPatch returns the merge patch that sets the replicas and the replicas annotation of a Deployment or
+// StatefulSet, the annotation is removed if it isn't in meta. A patch only needs the patch permission on the workload
+func getReplicasPatch(meta metav1.ObjectMeta, replicas *int32) []byte {
+	var annotation interface{}
+	if value, ok := meta.Annotations[ReplicasAnnotation]; ok {
+		annotation = value
+	}
+	patch, _ := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{"annotations": map[string]interface{}{ReplicasAnnotation: annotation}},
+		"spec":     map[string]interface{}{"replicas": replicas},
+	})
+	return patch
+}
+
+// isStopped returns true if the Deployment or StatefulSet was stopped by stopReplicas and is still scaled to 0
+func isStopped(meta metav1.ObjectMeta, replicas *int32) bool {
+	_, ok := meta.Annotations[ReplicasAnnotation]
+	return ok && replicas != nil && *replicas == 0
+}
@@ -0,0 +1,114 @@
+/*
+Copyright (C) 2020 Synopsys, Inc.
+
+Licensed to the Apache Software Foundation (ASF) under one
+or more contributor license agreements. See the NOTICE file
+distributed with this work for additional information
+regarding copyright ownership. The ASF licenses this file
+to you under the Apache License, Version 2.0 (the
+"License"); you may not use this file except in compliance
+with the License. You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing,
+software distributed under the License is distributed on an
+"AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+KIND, either express or implied. See the License for the
+specific language governing permissions and limitations
+under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetReleaseWorkloads(t *testing.T) {
+	manifest := `---
+# Source: polaris/templates/service.yaml
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+---
+# Source: polaris/templates/deployment.yaml
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+---
+# Source: polaris/templates/statefulset.yaml
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: eventstore
+`
+	assert.Equal(t, ReleaseWorkloads{Deployments: []string{"web"}, StatefulSets: []string{"eventstore"}}, GetReleaseWorkloads(manifest))
+	assert.Equal(t, ReleaseWorkloads{Deployments: []string{}, StatefulSets: []string{}}, GetReleaseWorkloads(""))
+}
+
+func TestStopAndStartReplicas(t *testing.T) {
+	three := int32(3)
+	var tests = []struct {
+		description      string
+		replicas         *int32
+		expectedRecorded string
+	}{
+		{description: "replicas are set", replicas: &three, expectedRecorded: "3"},
+		{description: "replicas default to 1", replicas: nil, expectedRecorded: "1"},
+	}
+
+	for _, test := range tests {
+		meta := metav1.ObjectMeta{}
+		replicas := test.replicas
+
+		assert.True(t, stopReplicas(&meta, &replicas), test.description)
+		assert.Equal(t, test.expectedRecorded, meta.Annotations[ReplicasAnnotation], test.description)
+		assert.Equal(t, int32(0), *replicas, test.description)
+		assert.True(t, isStopped(meta, replicas), test.description)
+
+		// A second stop keeps the recorded replicas
+		assert.False(t, stopReplicas(&meta, &replicas), test.description)
+		assert.Equal(t, test.expectedRecorded, meta.Annotations[ReplicasAnnotation], test.description)
+
+		started, err := startReplicas(&meta, &replicas)
+		assert.Nil(t, err, test.description)
+		assert.True(t, started, test.description)
+		assert.Equal(t, test.expectedRecorded, fmt.Sprintf("%d", *replicas), test.description)
+		assert.NotContains(t, meta.Annotations, ReplicasAnnotation, test.description)
+		assert.False(t, isStopped(meta, replicas), test.description)
+
+		// A second start has nothing to restore
+		started, err = startReplicas(&meta, &replicas)
+		assert.Nil(t, err, test.description)
+		assert.False(t, started, test.description)
+	}
+
+	// A workload that was scaled up without removing the annotation is stopped again
+	meta := metav1.ObjectMeta{Annotations: map[string]string{ReplicasAnnotation: "1"}}
+	replicas := &three
+	assert.False(t, isStopped(meta, replicas))
+	assert.True(t, stopReplicas(&meta, &replicas))
+	assert.Equal(t, "3", meta.Annotations[ReplicasAnnotation])
+	assert.Equal(t, int32(0), *replicas)
+
+	replicas = &three
+	_, err := startReplicas(&metav1.ObjectMeta{Annotations: map[string]string{ReplicasAnnotation: "many"}}, &replicas)
+	assert.NotNil(t, err)
+}
+
+func TestGetReplicasPatch(t *testing.T) {
+	zero := int32(0)
+	meta := metav1.ObjectMeta{Annotations: map[string]string{ReplicasAnnotation: "3"}}
+	assert.JSONEq(t, `{"metadata":{"annotations":{"synopsys.com/replicas":"3"}},"spec":{"replicas":0}}`, string(getReplicasPatch(meta, &zero)))
+
+	// The annotation is removed when the workload is started
+	three := int32(3)
+	assert.JSONEq(t, `{"metadata":{"annotations":{"synopsys.com/replicas":null}},"spec":{"replicas":3}}`, string(getReplicasPatch(metav1.ObjectMeta{}, &three)))
+}
@@ -0,0 +1,256 @@
+/*
+Copyright (C) 2020 Synopsys, Inc.
+
+Licensed to the Apache Software Foundation (ASF) under one
+or more contributor license agreements. See the NOTICE file
+distributed with this work for additional information
+regarding copyright ownership. The ASF licenses this file
+to you under the Apache License, Version 2.0 (the
+"License"); you may not use this file except in compliance
+with the License. You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing,
+software distributed under the License is distributed on an
+"AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+KIND, either express or implied. See the License for the
+specific language governing permissions and limitations
+under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+	"strings"
+
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Labels of the resources that run the scheduled start and stop of an instance
+const (
+	scheduleProductLabel  = "synopsys.com/schedule-product"
+	scheduleInstanceLabel = "synopsys.com/schedule-instance"
+	scheduleActionLabel   = "synopsys.com/schedule-action"
+)
+
+// Schedule is a command that a CronJob runs on a cron schedule to start or stop an instance
+type Schedule struct {
+	Action   string
+	Schedule string
+	Command  []string
+}
+
+// ScheduleSummary is a CronJob that starts or stops an instance
+type ScheduleSummary struct {
+	Product      string `json:"product"`
+	Name         string `json:"name"`
+	Namespace    string `json:"namespace"`
+	Action       string `json:"action"`
+	Schedule     string `json:"schedule"`
+	LastSchedule string `json:"lastSchedule,omitempty"`
+}
+
+// ValidateCronSchedule returns an error if schedule isn't a cron schedule with 5 fields (e.g. 0 20 * * 1-5)
+// or one of the predefined schedules (e.g. @daily)
+func ValidateCronSchedule(schedule string) error {
+	if strings.HasPrefix(schedule, "@") {
+		return nil
+	}
+	if fields := strings.Fields(schedule); len(fields) != 5 {
+		return fmt.Errorf("'%s' is an invalid schedule, it must have 5 fields: minute hour day-of-month month day-of-week", schedule)
+	}
+	return nil
+}
+
+// GetScheduleResourceName returns the name of the resources that run the schedules of an instance
+func GetScheduleResourceName(product, name string) string {
+	if product == name {
+		return fmt.Sprintf("%s-schedule", product)
+	}
+	return fmt.Sprintf("%s-%s-schedule", product, name)
+}
+
+// getScheduleLabels returns the labels of the resources that run the schedules of an instance
+func getScheduleLabels(product, name string) map[string]string {
+	return map[string]string{scheduleProductLabel: product, scheduleInstanceLabel: name}
+}
+
+// NewScheduleRole returns a role with the permissions that the start and stop commands need to read the Helm
+// release of an instance and scale its workloads. The workloads are limited to the ones in the release when the
+// schedules are applied
+func NewScheduleRole(namespace, product, name string, workloads ReleaseWorkloads) *rbacv1.Role {
+	rules := []rbacv1.PolicyRule{
+		// Helm lists the releases from the secrets that it stores them in
+		{APIGroups: []string{""}, Resources: []string{"secrets"}, Verbs: []string{"list"}},
+	}
+	// a rule without resource names would allow all the resources of its type
+	if len(workloads.Deployments) > 0 {
+		rules = append(rules, rbacv1.PolicyRule{APIGroups: []string{"apps"}, Resources: []string{"deployments"}, ResourceNames: workloads.Deployments, Verbs: []string{"get", "patch"}})
+	}
+	if len(workloads.StatefulSets) > 0 {
+		rules = append(rules, rbacv1.PolicyRule{APIGroups: []string{"apps"}, Resources: []string{"statefulsets"}, ResourceNames: workloads.StatefulSets, Verbs: []string{"get", "patch"}})
+	}
+	return &rbacv1.Role{
+		ObjectMeta: metav1.ObjectMeta{Name: GetScheduleResourceName(product, name), Namespace: namespace, Labels: getScheduleLabels(product, name)},
+		Rules:      rules,
+	}
+}
+
+// NewScheduleCronJob returns a CronJob that runs the command of the schedule with the image
+func NewScheduleCronJob(namespace, product, name, image string, schedule Schedule) *batchv1beta1.CronJob {
+	labels := getScheduleLabels(product, name)
+	labels[scheduleActionLabel] = schedule.Action
+	successfulJobsHistoryLimit := int32(1)
+	failedJobsHistoryLimit := int32(3)
+	backoffLimit := int32(2)
+	return &batchv1beta1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("%s-%s", GetScheduleResourceName(product, name), schedule.Action), Namespace: namespace, Labels: labels},
+		Spec: batchv1beta1.CronJobSpec{
+			Schedule:                   schedule.Schedule,
+			ConcurrencyPolicy:          batchv1beta1.ForbidConcurrent,
+			SuccessfulJobsHistoryLimit: &successfulJobsHistoryLimit,
+			FailedJobsHistoryLimit:     &failedJobsHistoryLimit,
+			JobTemplate: batchv1beta1.JobTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: batchv1.JobSpec{
+					BackoffLimit: &backoffLimit,
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{Labels: labels},
+						Spec: corev1.PodSpec{
+							ServiceAccountName: GetScheduleResourceName(product, name),
+							RestartPolicy:      corev1.RestartPolicyOnFailure,
+							Containers: []corev1.Container{{
+								Name:    schedule.Action,
+								Image:   image,
+								Command: schedule.Command,
+								// The chart cache is kept in the home directory
+								Env: []corev1.EnvVar{{Name: "HOME", Value: "/tmp"}},
+							}},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// ApplySchedules creates or updates the CronJobs of the schedules of an instance and the service account
+// and role that they run with. The role allows the CronJobs to scale the workloads of the instance. The
+// CronJobs of the other actions are kept
+func ApplySchedules(clientset *kubernetes.Clientset, namespace, product, name, image string, workloads ReleaseWorkloads, schedules []Schedule) error {
+	resourceName := GetScheduleResourceName(product, name)
+	labels := getScheduleLabels(product, name)
+
+	serviceAccount := &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: resourceName, Namespace: namespace, Labels: labels}}
+	if _, err := clientset.CoreV1().ServiceAccounts(namespace).Create(serviceAccount); err != nil && !k8serrors.IsAlreadyExists(err) {
+		return fmt.Errorf("unable to create service account '%s' in namespace '%s' due to %+v", resourceName, namespace, err)
+	}
+
+	role := NewScheduleRole(namespace, product, name, workloads)
+	if _, err := clientset.RbacV1().Roles(namespace).Create(role); k8serrors.IsAlreadyExists(err) {
+		if _, err := clientset.RbacV1().Roles(namespace).Update(role); err != nil {
+			return fmt.Errorf("unable to update role '%s' in namespace '%s' due to %+v", resourceName, namespace, err)
+		}
+	} else if err != nil {
+		return fmt.Errorf("unable to create role '%s' in namespace '%s' due to %+v", resourceName, namespace, err)
+	}
+
+	roleBinding := &rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: resourceName, Namespace: namespace, Labels: labels},
+		Subjects:   []rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Name: resourceName, Namespace: namespace}},
+		RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "Role", Name: resourceName},
+	}
+	if _, err := clientset.RbacV1().RoleBindings(namespace).Create(roleBinding); err != nil && !k8serrors.IsAlreadyExists(err) {
+		return fmt.Errorf("unable to create role binding '%s' in namespace '%s' due to %+v", resourceName, namespace, err)
+	}
+
+	for _, schedule := range schedules {
+		cronJob := NewScheduleCronJob(namespace, product, name, image, schedule)
+		existingCronJob, err := clientset.BatchV1beta1().CronJobs(namespace).Get(cronJob.Name, metav1.GetOptions{})
+		if k8serrors.IsNotFound(err) {
+			if _, err := clientset.BatchV1beta1().CronJobs(namespace).Create(cronJob); err != nil {
+				return fmt.Errorf("unable to create cron job '%s' in namespace '%s' due to %+v", cronJob.Name, namespace, err)
+			}
+			continue
+		} else if err != nil {
+			return fmt.Errorf("unable to get cron job '%s' in namespace '%s' due to %+v", cronJob.Name, namespace, err)
+		}
+		existingCronJob.Labels = cronJob.Labels
+		existingCronJob.Spec = cronJob.Spec
+		if _, err := clientset.BatchV1beta1().CronJobs(namespace).Update(existingCronJob); err != nil {
+			return fmt.Errorf("unable to update cron job '%s' in namespace '%s' due to %+v", cronJob.Name, namespace, err)
+		}
+	}
+	return nil
+}
+
+// ListSchedules returns the schedules in the namespace, or in all namespaces if it is empty. The schedules
+// can be limited to the instances of a product, and to an instance with a name
+func ListSchedules(clientset *kubernetes.Clientset, namespace, product, name string) ([]ScheduleSummary, error) {
+	labelSelector := scheduleProductLabel
+	if len(product) > 0 {
+		labelSelector = fmt.Sprintf("%s=%s", scheduleProductLabel, product)
+		if len(name) > 0 {
+			labelSelector = fmt.Sprintf("%s,%s=%s", labelSelector, scheduleInstanceLabel, name)
+		}
+	}
+	cronJobs, err := clientset.BatchV1beta1().CronJobs(namespace).List(metav1.ListOptions{LabelSelector: labelSelector})
+	if err != nil {
+		return nil, fmt.Errorf("unable to list the cron jobs due to %+v", err)
+	}
+	schedules := []ScheduleSummary{}
+	for _, cronJob := range cronJobs.Items {
+		schedules = append(schedules, GetScheduleSummary(cronJob))
+	}
+	return schedules, nil
+}
+
+// GetScheduleSummary returns the instance, the action and the schedule of a CronJob
+func GetScheduleSummary(cronJob batchv1beta1.CronJob) ScheduleSummary {
+	summary := ScheduleSummary{
+		Product:   cronJob.Labels[scheduleProductLabel],
+		Name:      cronJob.Labels[scheduleInstanceLabel],
+		Namespace: cronJob.Namespace,
+		Action:    cronJob.Labels[scheduleActionLabel],
+		Schedule:  cronJob.Spec.Schedule,
+	}
+	if cronJob.Status.LastScheduleTime != nil {
+		summary.LastSchedule = cronJob.Status.LastScheduleTime.Format("2006-01-02 15:04:05 MST")
+	}
+	return summary
+}
+
+// DeleteSchedules deletes the CronJobs of the schedules of an instance and the service account and role that
+// they run with. It returns false if the instance doesn't have any schedules
+func DeleteSchedules(clientset *kubernetes.Clientset, namespace, product, name string) (bool, error) {
+	resourceName := GetScheduleResourceName(product, name)
+	labelSelector := fmt.Sprintf("%s=%s,%s=%s", scheduleProductLabel, product, scheduleInstanceLabel, name)
+	cronJobs, err := clientset.BatchV1beta1().CronJobs(namespace).List(metav1.ListOptions{LabelSelector: labelSelector})
+	if err != nil {
+		return false, fmt.Errorf("unable to list the cron jobs in namespace '%s' due to %+v", namespace, err)
+	}
+	propagationPolicy := metav1.DeletePropagationBackground
+	for _, cronJob := range cronJobs.Items {
+		if err := clientset.BatchV1beta1().CronJobs(namespace).Delete(cronJob.Name, &metav1.DeleteOptions{PropagationPolicy: &propagationPolicy}); err != nil && !k8serrors.IsNotFound(err) {
+			return false, fmt.Errorf("unable to delete cron job '%s' in namespace '%s' due to %+v", cronJob.Name, namespace, err)
+		}
+	}
+	if err := clientset.RbacV1().RoleBindings(namespace).Delete(resourceName, &metav1.DeleteOptions{}); err != nil && !k8serrors.IsNotFound(err) {
+		return false, fmt.Errorf("unable to delete role binding '%s' in namespace '%s' due to %+v", resourceName, namespace, err)
+	}
+	if err := clientset.RbacV1().Roles(namespace).Delete(resourceName, &metav1.DeleteOptions{}); err != nil && !k8serrors.IsNotFound(err) {
+		return false, fmt.Errorf("unable to delete role '%s' in namespace '%s' due to %+v", resourceName, namespace, err)
+	}
+	if err := clientset.CoreV1().ServiceAccounts(namespace).Delete(resourceName, &metav1.DeleteOptions{}); err != nil && !k8serrors.IsNotFound(err) {
+		return false, fmt.Errorf("unable to delete service account '%s' in namespace '%s' due to %+v", resourceName, namespace, err)
+	}
+	return len(cronJobs.Items) > 0, nil
+}
@@ -0,0 +1,84 @@
+/*
+Copyright (C) 2020 Synopsys, Inc.
+
+Licensed to the Apache Software Foundation (ASF) under one
+or more contributor license agreements. See the NOTICE file
+distributed with this work for additional information
+regarding copyright ownership. The ASF licenses this file
+to you under the Apache License, Version 2.0 (the
+"License"); you may not use this file except in compliance
+with the License. You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing,
+software distributed under the License is distributed on an
+"AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+KIND, either express or implied. See the License for the
+specific language governing permissions and limitations
+under the License.
+*/
+
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestValidateCronSchedule(t *testing.T) {
+	var tests = []struct {
+		schedule string
+		valid    bool
+	}{
+		{schedule: "0 20 * * 1-5", valid: true},
+		{schedule: "@daily", valid: true},
+		{schedule: "0 20 * *", valid: false},
+		{schedule: "", valid: false},
+	}
+
+	for _, test := range tests {
+		err := ValidateCronSchedule(test.schedule)
+		assert.Equal(t, test.valid, err == nil, test.schedule)
+	}
+}
+
+func TestGetScheduleResourceName(t *testing.T) {
+	assert.Equal(t, "blackduck-bd1-schedule", GetScheduleResourceName("blackduck", "bd1"))
+	assert.Equal(t, "polaris-schedule", GetScheduleResourceName("polaris", "polaris"))
+}
+
+func TestNewScheduleRole(t *testing.T) {
+	role := NewScheduleRole("ns", "blackduck", "bd1", ReleaseWorkloads{Deployments: []string{"bd1-blackduck-webapp", "bd1-blackduck-postgres"}, StatefulSets: []string{}})
+
+	assert.Equal(t, "blackduck-bd1-schedule", role.Name)
+	assert.Equal(t, []rbacv1.PolicyRule{
+		{APIGroups: []string{""}, Resources: []string{"secrets"}, Verbs: []string{"list"}},
+		{APIGroups: []string{"apps"}, Resources: []string{"deployments"}, ResourceNames: []string{"bd1-blackduck-webapp", "bd1-blackduck-postgres"}, Verbs: []string{"get", "patch"}},
+	}, role.Rules)
+}
+
+func TestNewScheduleCronJob(t *testing.T) {
+	command := []string{"synopsysctl", "stop", "blackduck", "bd1", "-n", "ns"}
+	cronJob := NewScheduleCronJob("ns", "blackduck", "bd1", "synopsysctl:latest", Schedule{Action: "stop", Schedule: "0 20 * * 1-5", Command: command})
+
+	assert.Equal(t, "blackduck-bd1-schedule-stop", cronJob.Name)
+	assert.Equal(t, "0 20 * * 1-5", cronJob.Spec.Schedule)
+	assert.Equal(t, batchv1beta1.ForbidConcurrent, cronJob.Spec.ConcurrencyPolicy)
+	podSpec := cronJob.Spec.JobTemplate.Spec.Template.Spec
+	assert.Equal(t, "blackduck-bd1-schedule", podSpec.ServiceAccountName)
+	assert.Equal(t, "synopsysctl:latest", podSpec.Containers[0].Image)
+	assert.Equal(t, command, podSpec.Containers[0].Command)
+
+	summary := GetScheduleSummary(*cronJob)
+	assert.Equal(t, ScheduleSummary{Product: "blackduck", Name: "bd1", Namespace: "ns", Action: "stop", Schedule: "0 20 * * 1-5"}, summary)
+
+	lastSchedule := metav1.NewTime(time.Date(2020, 4, 1, 20, 0, 0, 0, time.UTC))
+	cronJob.Status.LastScheduleTime = &lastSchedule
+	assert.Equal(t, "2020-04-01 20:00:00 UTC", GetScheduleSummary(*cronJob).LastSchedule)
+}
@@ -0,0 +1,121 @@
+/*
+Copyright (C) 2020 Synopsys, Inc.
+
+Licensed to the Apache Software Foundation (ASF) under one
+or more contributor license agreements. See the NOTICE file
+distributed with this work for additional information
+regarding copyright ownership. The ASF licenses this file
+to you under the Apache License, Version 2.0 (the
+"License"); you may not use this file except in compliance
+with the License. You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing,
+software distributed under the License is distributed on an
+"AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+KIND, either express or implied. See the License for the
+specific language governing permissions and limitations
+under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Labels of the copies of the secrets that synopsysctl manages outside of the charts
+const (
+	secretRevisionReleaseLabel = "synopsys.com/release"
+	secretRevisionOfLabel      = "synopsys.com/revision-of"
+	secretRevisionLabel        = "synopsys.com/revision"
+)
+
+// SaveSecretRevision saves a copy of the secret as it is at a revision of the release, so that it
+// can be restored when the release is rolled back to the revision
+func SaveSecretRevision(clientset *kubernetes.Clientset, namespace, releaseName, secretName string, revision int) error {
+	secret, err := GetSecret(clientset, namespace, secretName)
+	if k8serrors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("unable to get secret '%s' in namespace '%s' due to %+v", secretName, namespace, err)
+	}
+	secretRevision := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-revision-%d", secretName, revision),
+			Namespace: namespace,
+			Labels: map[string]string{
+				secretRevisionReleaseLabel: releaseName,
+				secretRevisionOfLabel:      secretName,
+				secretRevisionLabel:        strconv.Itoa(revision),
+			},
+		},
+		Type: secret.Type,
+		Data: secret.Data,
+	}
+	// The first copy of a revision is kept, it was saved before the secret was changed
+	if _, err := clientset.CoreV1().Secrets(namespace).Create(secretRevision); err != nil && !k8serrors.IsAlreadyExists(err) {
+		return fmt.Errorf("unable to save revision %d of secret '%s' in namespace '%s' due to %+v", revision, secretName, namespace, err)
+	}
+	return nil
+}
+
+// RestoreSecretRevision restores the data of the secret to what it was at a revision of the release and
+// returns false if the secret hasn't changed since the revision
+func RestoreSecretRevision(clientset *kubernetes.Clientset, namespace, releaseName, secretName string, revision int) (bool, error) {
+	secretRevisions, err := ListSecrets(clientset, namespace, fmt.Sprintf("%s=%s, %s=%s", secretRevisionReleaseLabel, releaseName, secretRevisionOfLabel, secretName))
+	if err != nil {
+		return false, fmt.Errorf("unable to list the revisions of secret '%s' in namespace '%s' due to %+v", secretName, namespace, err)
+	}
+	secretRevision := findSecretRevision(secretRevisions.Items, revision)
+	if secretRevision == nil {
+		return false, nil
+	}
+
+	secret, err := GetSecret(clientset, namespace, secretName)
+	if k8serrors.IsNotFound(err) {
+		secret = &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: secretName, Namespace: namespace}, Type: secretRevision.Type, Data: secretRevision.Data}
+		if _, err := clientset.CoreV1().Secrets(namespace).Create(secret); err != nil {
+			return false, fmt.Errorf("unable to create secret '%s' in namespace '%s' due to %+v", secretName, namespace, err)
+		}
+		return true, nil
+	} else if err != nil {
+		return false, fmt.Errorf("unable to get secret '%s' in namespace '%s' due to %+v", secretName, namespace, err)
+	}
+	secret.Data = secretRevision.Data
+	secret.StringData = nil
+	if _, err := UpdateSecret(clientset, namespace, secret); err != nil {
+		return false, fmt.Errorf("unable to restore secret '%s' in namespace '%s' due to %+v", secretName, namespace, err)
+	}
+	return true, nil
+}
+
+// DeleteSecretRevisions deletes the saved revisions of the secrets of a release
+func DeleteSecretRevisions(clientset *kubernetes.Clientset, namespace, releaseName string) error {
+	return clientset.CoreV1().Secrets(namespace).DeleteCollection(&metav1.DeleteOptions{}, metav1.ListOptions{LabelSelector: fmt.Sprintf("%s=%s", secretRevisionReleaseLabel, releaseName)})
+}
+
+// findSecretRevision returns the saved secret that holds the data of a revision, a secret is only saved
+// before it is changed, so it is the oldest saved secret from the revision onwards
+func findSecretRevision(secretRevisions []corev1.Secret, revision int) *corev1.Secret {
+	var found *corev1.Secret
+	foundRevision := 0
+	for i, secretRevision := range secretRevisions {
+		savedRevision, err := strconv.Atoi(secretRevision.Labels[secretRevisionLabel])
+		if err != nil || savedRevision < revision {
+			continue
+		}
+		if found == nil || savedRevision < foundRevision {
+			found = &secretRevisions[i]
+			foundRevision = savedRevision
+		}
+	}
+	return found
+}
@@ -0,0 +1,62 @@
+/*
+Copyright (C) 2020 Synopsys, Inc.
+
+Licensed to the Apache Software Foundation (ASF) under one
+or more contributor license agreements. See the NOTICE file
+distributed with this work for additional information
+regarding copyright ownership. The ASF licenses this file
+to you under the Apache License, Version 2.0 (the
+"License"); you may not use this file except in compliance
+with the License. You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing,
+software distributed under the License is distributed on an
+"AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+KIND, either express or implied. See the License for the
+specific language governing permissions and limitations
+under the License.
+*/
+
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestFindSecretRevision(t *testing.T) {
+	newSecretRevision := func(revision string) corev1.Secret {
+		return corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+			Name:   "bd-blackduck-webserver-certificate-revision-" + revision,
+			Labels: map[string]string{secretRevisionLabel: revision},
+		}}
+	}
+	secretRevisions := []corev1.Secret{newSecretRevision("5"), newSecretRevision("2"), newSecretRevision("invalid"), newSecretRevision("7")}
+
+	var tests = []struct {
+		revision int
+		expected string
+	}{
+		{revision: 1, expected: "bd-blackduck-webserver-certificate-revision-2"},
+		{revision: 2, expected: "bd-blackduck-webserver-certificate-revision-2"},
+		{revision: 3, expected: "bd-blackduck-webserver-certificate-revision-5"},
+		{revision: 6, expected: "bd-blackduck-webserver-certificate-revision-7"},
+		{revision: 8, expected: ""},
+	}
+
+	for _, test := range tests {
+		found := findSecretRevision(secretRevisions, test.revision)
+		if len(test.expected) == 0 {
+			assert.Nil(t, found, "revision %d", test.revision)
+			continue
+		}
+		if assert.NotNil(t, found, "revision %d", test.revision) {
+			assert.Equal(t, test.expected, found.Name, "revision %d", test.revision)
+		}
+	}
+}
@@ -0,0 +1,302 @@
+/*
+Copyright (C) 2020 Synopsys, Inc.
+
+Licensed to the Apache Software Foundation (ASF) under one
+or more contributor license agreements. See the NOTICE file
+distributed with this work for additional information
+regarding copyright ownership. The ASF licenses this file
+to you under the Apache License, Version 2.0 (the
+"License"); you may not use this file except in compliance
+with the License. You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing,
+software distributed under the License is distributed on an
+"AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+KIND, either express or implied. See the License for the
+specific language governing permissions and limitations
+under the License.
+*/
+
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Labels and annotations of the volume snapshots of an instance
+const (
+	snapshotProductLabel  = "synopsys.com/snapshot-product"
+	snapshotInstanceLabel = "synopsys.com/snapshot-instance"
+	snapshotSetLabel      = "synopsys.com/snapshot-set"
+	snapshotPVCAnnotation = "synopsys.com/snapshot-pvc"
+)
+
+// SnapshotsAnnotation is the annotation of a Helm release revision with the volume snapshots taken of the instance before it was changed
+const SnapshotsAnnotation = "synopsys.com/snapshots"
+
+// volumeSnapshotGroup is the API group of the CSI volume snapshots
+const volumeSnapshotGroup = "snapshot.storage.k8s.io"
+
+// VolumeSnapshotSummary is a volume snapshot of a PVC of an instance
+type VolumeSnapshotSummary struct {
+	Set         string `json:"set"`
+	Name        string `json:"name"`
+	PVC         string `json:"pvc"`
+	ReadyToUse  bool   `json:"readyToUse"`
+	RestoreSize string `json:"restoreSize,omitempty"`
+	Created     string `json:"created"`
+}
+
+// GetVolumeSnapshotResource returns the newest version of the VolumeSnapshot API that the cluster serves
+func GetVolumeSnapshotResource(clientset *kubernetes.Clientset) (schema.GroupVersionResource, error) {
+	for _, version := range []string{"v1", "v1beta1"} {
+		if _, err := clientset.Discovery().ServerResourcesForGroupVersion(fmt.Sprintf("%s/%s", volumeSnapshotGroup, version)); err == nil {
+			return schema.GroupVersionResource{Group: volumeSnapshotGroup, Version: version, Resource: "volumesnapshots"}, nil
+		}
+	}
+	return schema.GroupVersionResource{}, fmt.Errorf("the cluster doesn't serve the %s API, volume snapshots need the CSI snapshot controller and its CRDs", volumeSnapshotGroup)
+}
+
+// NewSnapshotSet returns the name of the set of volume snapshots taken at t
+func NewSnapshotSet(t time.Time) string {
+	return t.UTC().Format("20060102150405")
+}
+
+// GetSnapshotLabelSelector returns the label selector of the volume snapshots of an instance, or of one set of them if set isn't empty
+func GetSnapshotLabelSelector(product, name, set string) string {
+	labelSelector := fmt.Sprintf("%s=%s,%s=%s", snapshotProductLabel, product, snapshotInstanceLabel, name)
+	if len(set) > 0 {
+		labelSelector = fmt.Sprintf("%s,%s=%s", labelSelector, snapshotSetLabel, set)
+	}
+	return labelSelector
+}
+
+// getSnapshotPVC returns the PVC without its status and the fields that Kubernetes sets, so that it can be recreated from a snapshot
+func getSnapshotPVC(pvc corev1.PersistentVolumeClaim) corev1.PersistentVolumeClaim {
+	annotations := map[string]string{}
+	for key, value := range pvc.Annotations {
+		if !strings.Contains(key, "kubernetes.io/") {
+			annotations[key] = value
+		}
+	}
+	return corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        pvc.Name,
+			Namespace:   pvc.Namespace,
+			Labels:      pvc.Labels,
+			Annotations: annotations,
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes:      pvc.Spec.AccessModes,
+			Resources:        pvc.Spec.Resources,
+			StorageClassName: pvc.Spec.StorageClassName,
+			VolumeMode:       pvc.Spec.VolumeMode,
+		},
+	}
+}
+
+// NewVolumeSnapshot returns a volume snapshot of the PVC in a set of snapshots of an instance. The PVC is saved in an
+// annotation of the snapshot so that it can be recreated from it
+func NewVolumeSnapshot(resource schema.GroupVersionResource, pvc corev1.PersistentVolumeClaim, product, name, set, className string) (*unstructured.Unstructured, error) {
+	savedPVC, err := json.Marshal(getSnapshotPVC(pvc))
+	if err != nil {
+		return nil, err
+	}
+	spec := map[string]interface{}{
+		"source": map[string]interface{}{"persistentVolumeClaimName": pvc.Name},
+	}
+	if len(className) > 0 {
+		spec["volumeSnapshotClassName"] = className
+	}
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": resource.GroupVersion().String(),
+			"kind":       "VolumeSnapshot",
+			"metadata": map[string]interface{}{
+				"name":        fmt.Sprintf("%s-%s", pvc.Name, set),
+				"namespace":   pvc.Namespace,
+				"labels":      map[string]interface{}{snapshotProductLabel: product, snapshotInstanceLabel: name, snapshotSetLabel: set},
+				"annotations": map[string]interface{}{snapshotPVCAnnotation: string(savedPVC)},
+			},
+			"spec": spec,
+		},
+	}, nil
+}
+
+// NewRestoredPVC returns the PVC that the volume snapshot was taken of, with the snapshot as its data source
+func NewRestoredPVC(snapshot unstructured.Unstructured) (*corev1.PersistentVolumeClaim, error) {
+	savedPVC, ok := snapshot.GetAnnotations()[snapshotPVCAnnotation]
+	if !ok {
+		return nil, fmt.Errorf("volume snapshot '%s' doesn't have the %s annotation", snapshot.GetName(), snapshotPVCAnnotation)
+	}
+	pvc := &corev1.PersistentVolumeClaim{}
+	if err := json.Unmarshal([]byte(savedPVC), pvc); err != nil {
+		return nil, fmt.Errorf("unable to read the PVC of volume snapshot '%s' due to %+v", snapshot.GetName(), err)
+	}
+	apiGroup := volumeSnapshotGroup
+	pvc.Spec.DataSource = &corev1.TypedLocalObjectReference{APIGroup: &apiGroup, Kind: "VolumeSnapshot", Name: snapshot.GetName()}
+	return pvc, nil
+}
+
+// GetVolumeSnapshotSummary returns the set, the PVC and the status of a volume snapshot
+func GetVolumeSnapshotSummary(snapshot unstructured.Unstructured) VolumeSnapshotSummary {
+	pvc, _, _ := unstructured.NestedString(snapshot.Object, "spec", "source", "persistentVolumeClaimName")
+	readyToUse, _, _ := unstructured.NestedBool(snapshot.Object, "status", "readyToUse")
+	restoreSize, _, _ := unstructured.NestedString(snapshot.Object, "status", "restoreSize")
+	return VolumeSnapshotSummary{
+		Set:         snapshot.GetLabels()[snapshotSetLabel],
+		Name:        snapshot.GetName(),
+		PVC:         pvc,
+		ReadyToUse:  readyToUse,
+		RestoreSize: restoreSize,
+		Created:     snapshot.GetCreationTimestamp().Format("2006-01-02 15:04:05 MST"),
+	}
+}
+
+// ListVolumeSnapshots returns the volume snapshots selected by labelSelector sorted by set and name
+func ListVolumeSnapshots(dynamicClient dynamic.Interface, resource schema.GroupVersionResource, namespace, labelSelector string) ([]unstructured.Unstructured, error) {
+	list, err := dynamicClient.Resource(resource).Namespace(namespace).List(metav1.ListOptions{LabelSelector: labelSelector})
+	if err != nil {
+		return nil, fmt.Errorf("unable to list the volume snapshots in namespace '%s' due to %+v", namespace, err)
+	}
+	snapshots := list.Items
+	sort.Slice(snapshots, func(i, j int) bool {
+		if iSet, jSet := snapshots[i].GetLabels()[snapshotSetLabel], snapshots[j].GetLabels()[snapshotSetLabel]; iSet != jSet {
+			return iSet < jSet
+		}
+		return snapshots[i].GetName() < snapshots[j].GetName()
+	})
+	return snapshots, nil
+}
+
+// CreateVolumeSnapshots takes a volume snapshot of each PVC selected by pvcLabelSelector in a new set of snapshots of the
+// instance, and waits until they are ready to use. It returns the set and the names of the snapshots
+func CreateVolumeSnapshots(clientset *kubernetes.Clientset, dynamicClient dynamic.Interface, namespace, product, name, pvcLabelSelector, className string, timeout time.Duration) (string, []string, error) {
+	resource, err := GetVolumeSnapshotResource(clientset)
+	if err != nil {
+		return "", nil, err
+	}
+	pvcs, err := ListPVCs(clientset, namespace, pvcLabelSelector)
+	if err != nil {
+		return "", nil, fmt.Errorf("unable to list the PVCs in namespace '%s' due to %+v", namespace, err)
+	}
+	if len(pvcs.Items) == 0 {
+		return "", nil, fmt.Errorf("%s '%s' doesn't have any PVCs in namespace '%s'", product, name, namespace)
+	}
+
+	set := NewSnapshotSet(time.Now())
+	names := []string{}
+	for _, pvc := range pvcs.Items {
+		snapshot, err := NewVolumeSnapshot(resource, pvc, product, name, set, className)
+		if err != nil {
+			return "", nil, err
+		}
+		if _, err := dynamicClient.Resource(resource).Namespace(namespace).Create(snapshot, metav1.CreateOptions{}); err != nil {
+			return "", nil, fmt.Errorf("unable to create volume snapshot '%s' in namespace '%s' due to %+v", snapshot.GetName(), namespace, err)
+		}
+		names = append(names, snapshot.GetName())
+	}
+
+	deadline := time.Now().Add(timeout)
+	for _, snapshotName := range names {
+		for {
+			snapshot, err := dynamicClient.Resource(resource).Namespace(namespace).Get(snapshotName, metav1.GetOptions{})
+			if err != nil {
+				return "", nil, fmt.Errorf("unable to get volume snapshot '%s' in namespace '%s' due to %+v", snapshotName, namespace, err)
+			}
+			if message, found, _ := unstructured.NestedString(snapshot.Object, "status", "error", "message"); found {
+				return "", nil, fmt.Errorf("volume snapshot '%s' failed: %s", snapshotName, message)
+			}
+			if GetVolumeSnapshotSummary(*snapshot).ReadyToUse {
+				break
+			}
+			if time.Now().After(deadline) {
+				return "", nil, fmt.Errorf("timed out after %s waiting for volume snapshot '%s' to be ready to use", timeout, snapshotName)
+			}
+			time.Sleep(waitInterval)
+		}
+	}
+	return set, names, nil
+}
+
+// RestoreVolumeSnapshots deletes the PVCs that the snapshots of the set were taken of, and recreates them from the
+// snapshots. The pods that use the PVCs must have been stopped. It returns the names of the PVCs
+func RestoreVolumeSnapshots(clientset *kubernetes.Clientset, dynamicClient dynamic.Interface, namespace, product, name, set string, timeout time.Duration) ([]string, error) {
+	resource, err := GetVolumeSnapshotResource(clientset)
+	if err != nil {
+		return nil, err
+	}
+	snapshots, err := ListVolumeSnapshots(dynamicClient, resource, namespace, GetSnapshotLabelSelector(product, name, set))
+	if err != nil {
+		return nil, err
+	}
+	if len(snapshots) == 0 {
+		return nil, fmt.Errorf("%s '%s' doesn't have volume snapshots in set '%s' in namespace '%s'", product, name, set, namespace)
+	}
+
+	pvcs := []*corev1.PersistentVolumeClaim{}
+	for _, snapshot := range snapshots {
+		if !GetVolumeSnapshotSummary(snapshot).ReadyToUse {
+			return nil, fmt.Errorf("volume snapshot '%s' isn't ready to use", snapshot.GetName())
+		}
+		pvc, err := NewRestoredPVC(snapshot)
+		if err != nil {
+			return nil, err
+		}
+		pvcs = append(pvcs, pvc)
+	}
+
+	names := []string{}
+	deadline := time.Now().Add(timeout)
+	for _, pvc := range pvcs {
+		if err := clientset.CoreV1().PersistentVolumeClaims(namespace).Delete(pvc.Name, &metav1.DeleteOptions{}); err != nil && !k8serrors.IsNotFound(err) {
+			return names, fmt.Errorf("unable to delete PVC '%s' in namespace '%s' due to %+v", pvc.Name, namespace, err)
+		}
+		for {
+			if _, err := GetPVC(clientset, namespace, pvc.Name); k8serrors.IsNotFound(err) {
+				break
+			}
+			if time.Now().After(deadline) {
+				return names, fmt.Errorf("timed out after %s waiting for PVC '%s' to be deleted", timeout, pvc.Name)
+			}
+			time.Sleep(waitInterval)
+		}
+		if _, err := clientset.CoreV1().PersistentVolumeClaims(namespace).Create(pvc); err != nil {
+			return names, fmt.Errorf("unable to create PVC '%s' in namespace '%s' due to %+v", pvc.Name, namespace, err)
+		}
+		names = append(names, pvc.Name)
+	}
+	return names, nil
+}
+
+// AnnotateReleaseSnapshots records the volume snapshots in an annotation of the secret of a Helm release revision
+func AnnotateReleaseSnapshots(clientset *kubernetes.Clientset, namespace, releaseName string, revision int, snapshotNames []string) error {
+	secretName := fmt.Sprintf("sh.helm.release.v1.%s.v%d", releaseName, revision)
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{SnapshotsAnnotation: strings.Join(snapshotNames, ",")},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := clientset.CoreV1().Secrets(namespace).Patch(secretName, types.MergePatchType, patch); err != nil {
+		return fmt.Errorf("unable to annotate secret '%s' in namespace '%s' due to %+v", secretName, namespace, err)
+	}
+	return nil
+}
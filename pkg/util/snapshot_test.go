@@ -0,0 +1,92 @@
+/*
+Copyright (C) 2020 Synopsys, Inc.
+
+Licensed to the Apache Software Foundation (ASF) under one
+or more contributor license agreements. See the NOTICE file
+distributed with this work for additional information
+regarding copyright ownership. The ASF licenses this file
+to you under the Apache License, Version 2.0 (the
+"License"); you may not use this file except in compliance
+with the License. You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing,
+software distributed under the License is distributed on an
+"AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+KIND, either express or implied. See the License for the
+specific language governing permissions and limitations
+under the License.
+*/
+
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestNewSnapshotSet(t *testing.T) {
+	assert.Equal(t, "20200501123000", NewSnapshotSet(time.Date(2020, time.May, 1, 12, 30, 0, 0, time.UTC)))
+}
+
+func TestGetSnapshotLabelSelector(t *testing.T) {
+	assert.Equal(t, "synopsys.com/snapshot-product=blackduck,synopsys.com/snapshot-instance=bd1", GetSnapshotLabelSelector("blackduck", "bd1", ""))
+	assert.Equal(t, "synopsys.com/snapshot-product=blackduck,synopsys.com/snapshot-instance=bd1,synopsys.com/snapshot-set=20200501123000", GetSnapshotLabelSelector("blackduck", "bd1", "20200501123000"))
+}
+
+func TestVolumeSnapshot(t *testing.T) {
+	storageClass := "standard"
+	pvc := corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "bd1-blackduck-postgres",
+			Namespace:       "ns",
+			Labels:          map[string]string{"app": "blackduck", "component": "pvc", "name": "bd1"},
+			Annotations:     map[string]string{"meta.helm.sh/release-name": "bd1", "pv.kubernetes.io/bind-completed": "yes"},
+			ResourceVersion: "42",
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			Resources:        corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("150Gi")}},
+			StorageClassName: &storageClass,
+			VolumeName:       "pvc-1234",
+		},
+		Status: corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimBound},
+	}
+	volumeSnapshotResource := schema.GroupVersionResource{Group: "snapshot.storage.k8s.io", Version: "v1beta1", Resource: "volumesnapshots"}
+
+	snapshot, err := NewVolumeSnapshot(volumeSnapshotResource, pvc, "blackduck", "bd1", "20200501123000", "csi-snapclass")
+	assert.Nil(t, err)
+	assert.Equal(t, "snapshot.storage.k8s.io/v1beta1", snapshot.GetAPIVersion())
+	assert.Equal(t, "bd1-blackduck-postgres-20200501123000", snapshot.GetName())
+	assert.Equal(t, "20200501123000", snapshot.GetLabels()["synopsys.com/snapshot-set"])
+	assert.Equal(t, "csi-snapclass", snapshot.Object["spec"].(map[string]interface{})["volumeSnapshotClassName"])
+
+	summary := GetVolumeSnapshotSummary(*snapshot)
+	assert.Equal(t, "bd1-blackduck-postgres", summary.PVC)
+	assert.False(t, summary.ReadyToUse)
+
+	restoredPVC, err := NewRestoredPVC(*snapshot)
+	assert.Nil(t, err)
+	assert.Equal(t, "bd1-blackduck-postgres", restoredPVC.Name)
+	assert.Equal(t, pvc.Labels, restoredPVC.Labels)
+	assert.Equal(t, map[string]string{"meta.helm.sh/release-name": "bd1"}, restoredPVC.Annotations)
+	assert.Empty(t, restoredPVC.ResourceVersion)
+	assert.Empty(t, restoredPVC.Spec.VolumeName)
+	assert.Equal(t, "standard", *restoredPVC.Spec.StorageClassName)
+	storage := restoredPVC.Spec.Resources.Requests[corev1.ResourceStorage]
+	assert.Equal(t, "150Gi", storage.String())
+	assert.Equal(t, "VolumeSnapshot", restoredPVC.Spec.DataSource.Kind)
+	assert.Equal(t, "bd1-blackduck-postgres-20200501123000", restoredPVC.Spec.DataSource.Name)
+	assert.Equal(t, "snapshot.storage.k8s.io", *restoredPVC.Spec.DataSource.APIGroup)
+
+	snapshot.SetAnnotations(nil)
+	_, err = NewRestoredPVC(*snapshot)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,317 @@
+/*
+Copyright (C) 2020 Synopsys, Inc.
+
+Licensed to the Apache Software Foundation (ASF) under one
+or more contributor license agreements. See the NOTICE file
+distributed with this work for additional information
+regarding copyright ownership. The ASF licenses this file
+to you under the Apache License, Version 2.0 (the
+"License"); you may not use this file except in compliance
+with the License. You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing,
+software distributed under the License is distributed on an
+"AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+KIND, either express or implied. See the License for the
+specific language governing permissions and limitations
+under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// DefaultWaitTimeout is how long to wait for an instance to be ready by default
+const DefaultWaitTimeout = 15 * time.Minute
+
+// waitInterval is how often the components of an instance are checked while waiting for them to be ready
+const waitInterval = 5 * time.Second
+
+// WaitForInstanceReady waits until the Deployments, StatefulSets, pods and PVCs selected by labelSelector are
+// ready and logs the progress of each component. On timeout, the error has the events of the pods that aren't ready
+func WaitForInstanceReady(clientset *kubernetes.Clientset, namespace, labelSelector string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	progress := map[string]string{}
+	for {
+		components, notReadyPods, err := getComponentStatuses(clientset, namespace, labelSelector)
+		if err != nil {
+			return err
+		}
+
+		names := []string{}
+		for name := range components {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		notReady := []string{}
+		for _, name := range names {
+			status := components[name]
+			if progress[name] != status.message {
+				log.Infof("%s: %s", name, status.message)
+				progress[name] = status.message
+			}
+			if !status.ready {
+				notReady = append(notReady, name)
+			}
+		}
+		if len(notReady) == 0 {
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			var message strings.Builder
+			fmt.Fprintf(&message, "timed out after %s waiting for %s to be ready", timeout, strings.Join(notReady, ", "))
+			for _, pod := range notReadyPods {
+				events, err := clientset.CoreV1().Events(namespace).List(metav1.ListOptions{FieldSelector: fmt.Sprintf("involvedObject.kind=Pod,involvedObject.name=%s", pod.Name)})
+				if err != nil {
+					return fmt.Errorf("%s, unable to get the events of pod '%s' due to %+v", message.String(), pod.Name, err)
+				}
+				fmt.Fprintf(&message, "\nevents of pod/%s:", pod.Name)
+				message.WriteString(formatEvents(events.Items))
+			}
+			return fmt.Errorf("%s", message.String())
+		}
+		time.Sleep(waitInterval)
+	}
+}
+
+// WaitForDeploymentRollout waits until all replicas of the latest version of a deployment are available and
+// the replicas of the previous versions are gone
+func WaitForDeploymentRollout(clientset *kubernetes.Clientset, namespace, name string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	progress := ""
+	for {
+		deployment, err := GetDeployment(clientset, namespace, name)
+		if err != nil {
+			return fmt.Errorf("unable to get deployment '%s' in namespace '%s' due to %+v", name, namespace, err)
+		}
+		status := getDeploymentRolloutStatus(*deployment)
+		if progress != status.message {
+			log.Infof("deployment/%s: %s", name, status.message)
+			progress = status.message
+		}
+		if status.ready {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out after %s waiting for deployment/%s to be ready", timeout, name)
+		}
+		time.Sleep(waitInterval)
+	}
+}
+
+// WaitForJob waits until the job has succeeded, and returns an error if the job fails or doesn't complete within the timeout
+func WaitForJob(clientset *kubernetes.Clientset, namespace, name string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		job, err := clientset.BatchV1().Jobs(namespace).Get(name, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("unable to get job '%s' in namespace '%s' due to %+v", name, namespace, err)
+		}
+		succeeded, err := getJobStatus(*job)
+		if err != nil {
+			// the containers of the jobs write their errors to the termination log
+			if pod, podErr := WaitForJobPod(clientset, namespace, name, 0); podErr == nil {
+				for _, status := range pod.Status.ContainerStatuses {
+					if status.State.Terminated != nil && len(status.State.Terminated.Message) > 0 {
+						return fmt.Errorf("%s: %s", err, strings.TrimSpace(status.State.Terminated.Message))
+					}
+				}
+			}
+			return err
+		}
+		if succeeded {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out after %s waiting for job/%s to complete", timeout, name)
+		}
+		time.Sleep(waitInterval)
+	}
+}
+
+// WaitForJobPod waits until the pod of the job has started and returns it
+func WaitForJobPod(clientset *kubernetes.Clientset, namespace, name string, timeout time.Duration) (*corev1.Pod, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		pods, err := ListPodsWithLabels(clientset, namespace, fmt.Sprintf("job-name=%s", name))
+		if err != nil {
+			return nil, fmt.Errorf("unable to list the pods of job '%s' in namespace '%s' due to %+v", name, namespace, err)
+		}
+		if len(pods.Items) > 0 && pods.Items[0].Status.Phase != corev1.PodPending {
+			return &pods.Items[0], nil
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("timed out after %s waiting for the pod of job/%s to start", timeout, name)
+		}
+		time.Sleep(waitInterval)
+	}
+}
+
+// WaitForPodsStopped waits until the pods selected by labelSelector are deleted or have completed
+func WaitForPodsStopped(clientset *kubernetes.Clientset, namespace, labelSelector string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		pods, err := ListPodsWithLabels(clientset, namespace, labelSelector)
+		if err != nil {
+			return fmt.Errorf("unable to list the pods in namespace '%s' due to %+v", namespace, err)
+		}
+		running := 0
+		for _, pod := range pods.Items {
+			if pod.Status.Phase != corev1.PodSucceeded && pod.Status.Phase != corev1.PodFailed {
+				running++
+			}
+		}
+		if running == 0 {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out after %s waiting for %d pod(s) to stop", timeout, running)
+		}
+		time.Sleep(waitInterval)
+	}
+}
+
+// componentStatus is the readiness of a component of an instance
+type componentStatus struct {
+	ready   bool
+	message string
+}
+
+// getComponentStatuses returns the status of the components selected by labelSelector and the pods that aren't ready
+func getComponentStatuses(clientset *kubernetes.Clientset, namespace, labelSelector string) (map[string]componentStatus, []corev1.Pod, error) {
+	components := map[string]componentStatus{}
+	deployments, err := ListDeployments(clientset, namespace, labelSelector)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to list the deployments in namespace '%s' due to %+v", namespace, err)
+	}
+	for _, deployment := range deployments.Items {
+		components[fmt.Sprintf("deployment/%s", deployment.Name)] = getDeploymentStatus(deployment)
+	}
+	statefulSets, err := clientset.AppsV1().StatefulSets(namespace).List(metav1.ListOptions{LabelSelector: labelSelector})
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to list the statefulsets in namespace '%s' due to %+v", namespace, err)
+	}
+	for _, statefulSet := range statefulSets.Items {
+		components[fmt.Sprintf("statefulset/%s", statefulSet.Name)] = getStatefulSetStatus(statefulSet)
+	}
+	pvcs, err := ListPVCs(clientset, namespace, labelSelector)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to list the persistent volume claims in namespace '%s' due to %+v", namespace, err)
+	}
+	for _, pvc := range pvcs.Items {
+		components[fmt.Sprintf("pvc/%s", pvc.Name)] = getPVCStatus(pvc)
+	}
+	pods, err := ListPodsWithLabels(clientset, namespace, labelSelector)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to list the pods in namespace '%s' due to %+v", namespace, err)
+	}
+	notReadyPods := []corev1.Pod{}
+	for _, pod := range pods.Items {
+		// Completed pods of jobs and the pods that are being replaced don't need to be ready
+		if pod.Status.Phase == corev1.PodSucceeded || pod.DeletionTimestamp != nil {
+			continue
+		}
+		status := getPodStatus(pod)
+		components[fmt.Sprintf("pod/%s", pod.Name)] = status
+		if !status.ready {
+			notReadyPods = append(notReadyPods, pod)
+		}
+	}
+	return components, notReadyPods, nil
+}
+
+// getDeploymentStatus returns whether all replicas of the latest version of a deployment are available
+func getDeploymentStatus(deployment appsv1.Deployment) componentStatus {
+	replicas := int32(1)
+	if deployment.Spec.Replicas != nil {
+		replicas = *deployment.Spec.Replicas
+	}
+	ready := deployment.Status.ObservedGeneration >= deployment.Generation && deployment.Status.UpdatedReplicas >= replicas && deployment.Status.AvailableReplicas >= replicas
+	return componentStatus{ready: ready, message: fmt.Sprintf("%d/%d ready", deployment.Status.ReadyReplicas, replicas)}
+}
+
+// getDeploymentRolloutStatus returns whether a deployment is available and has no replicas of its previous versions left
+func getDeploymentRolloutStatus(deployment appsv1.Deployment) componentStatus {
+	status := getDeploymentStatus(deployment)
+	if oldReplicas := deployment.Status.Replicas - deployment.Status.UpdatedReplicas; oldReplicas > 0 {
+		status.ready = false
+		status.message = fmt.Sprintf("%s, %d old replica(s) terminating", status.message, oldReplicas)
+	}
+	return status
+}
+
+// getJobStatus returns true if the job has succeeded, or an error if it has failed
+func getJobStatus(job batchv1.Job) (bool, error) {
+	if job.Status.Succeeded > 0 {
+		return true, nil
+	}
+	for _, condition := range job.Status.Conditions {
+		if condition.Type == batchv1.JobFailed && condition.Status == corev1.ConditionTrue {
+			return false, fmt.Errorf("job/%s failed: %s", job.Name, condition.Message)
+		}
+	}
+	return false, nil
+}
+
+// getStatefulSetStatus returns whether all replicas of a statefulset are ready
+func getStatefulSetStatus(statefulSet appsv1.StatefulSet) componentStatus {
+	replicas := int32(1)
+	if statefulSet.Spec.Replicas != nil {
+		replicas = *statefulSet.Spec.Replicas
+	}
+	ready := statefulSet.Status.ObservedGeneration >= statefulSet.Generation && statefulSet.Status.ReadyReplicas >= replicas
+	return componentStatus{ready: ready, message: fmt.Sprintf("%d/%d ready", statefulSet.Status.ReadyReplicas, replicas)}
+}
+
+// getPVCStatus returns whether a persistent volume claim is bound
+func getPVCStatus(pvc corev1.PersistentVolumeClaim) componentStatus {
+	return componentStatus{ready: pvc.Status.Phase == corev1.ClaimBound, message: string(pvc.Status.Phase)}
+}
+
+// getPodStatus returns whether a pod is ready, and the reason that a container is waiting if it isn't
+func getPodStatus(pod corev1.Pod) componentStatus {
+	ready := false
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady && condition.Status == corev1.ConditionTrue {
+			ready = true
+		}
+	}
+	readyContainers := 0
+	message := string(pod.Status.Phase)
+	for _, container := range pod.Status.ContainerStatuses {
+		if container.Ready {
+			readyContainers++
+		} else if container.State.Waiting != nil && len(container.State.Waiting.Reason) > 0 {
+			message = container.State.Waiting.Reason
+		}
+	}
+	return componentStatus{ready: ready, message: fmt.Sprintf("%s, %d/%d containers ready", message, readyContainers, len(pod.Spec.Containers))}
+}
+
+// formatEvents returns a line for each event, oldest first
+func formatEvents(events []corev1.Event) string {
+	sort.Slice(events, func(i, j int) bool { return events[i].LastTimestamp.Before(&events[j].LastTimestamp) })
+	var message strings.Builder
+	if len(events) == 0 {
+		message.WriteString("\n  no events")
+	}
+	for _, event := range events {
+		fmt.Fprintf(&message, "\n  %s %s: %s", event.Type, event.Reason, strings.TrimSpace(event.Message))
+	}
+	return message.String()
+}
@@ -0,0 +1,156 @@
+/*
+Copyright (C) 2020 Synopsys, Inc.
+
+Licensed to the Apache Software Foundation (ASF) under one
+or more contributor license agreements. See the NOTICE file
+distributed with this work for additional information
+regarding copyright ownership. The ASF licenses this file
+to you under the Apache License, Version 2.0 (the
+"License"); you may not use this file except in compliance
+with the License. You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing,
+software distributed under the License is distributed on an
+"AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+KIND, either express or implied. See the License for the
+specific language governing permissions and limitations
+under the License.
+*/
+
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetDeploymentStatus(t *testing.T) {
+	replicas := int32(2)
+	var tests = []struct {
+		description string
+		deployment  appsv1.Deployment
+		expected    componentStatus
+	}{
+		{
+			description: "all replicas are available",
+			deployment: appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Generation: 2},
+				Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+				Status:     appsv1.DeploymentStatus{ObservedGeneration: 2, UpdatedReplicas: 2, ReadyReplicas: 2, AvailableReplicas: 2},
+			},
+			expected: componentStatus{ready: true, message: "2/2 ready"},
+		},
+		{
+			description: "the latest version hasn't been observed",
+			deployment: appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Generation: 3},
+				Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+				Status:     appsv1.DeploymentStatus{ObservedGeneration: 2, UpdatedReplicas: 2, ReadyReplicas: 2, AvailableReplicas: 2},
+			},
+			expected: componentStatus{ready: false, message: "2/2 ready"},
+		},
+		{
+			description: "a replica isn't ready",
+			deployment: appsv1.Deployment{
+				Spec:   appsv1.DeploymentSpec{Replicas: &replicas},
+				Status: appsv1.DeploymentStatus{UpdatedReplicas: 2, ReadyReplicas: 1, AvailableReplicas: 1},
+			},
+			expected: componentStatus{ready: false, message: "1/2 ready"},
+		},
+		{
+			description: "replicas defaults to 1",
+			deployment: appsv1.Deployment{
+				Status: appsv1.DeploymentStatus{UpdatedReplicas: 1, ReadyReplicas: 1, AvailableReplicas: 1},
+			},
+			expected: componentStatus{ready: true, message: "1/1 ready"},
+		},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, getDeploymentStatus(test.deployment), test.description)
+	}
+}
+
+func TestGetDeploymentRolloutStatus(t *testing.T) {
+	replicas := int32(2)
+	var tests = []struct {
+		description string
+		deployment  appsv1.Deployment
+		expected    componentStatus
+	}{
+		{
+			description: "the rollout is complete",
+			deployment: appsv1.Deployment{
+				Spec:   appsv1.DeploymentSpec{Replicas: &replicas},
+				Status: appsv1.DeploymentStatus{Replicas: 2, UpdatedReplicas: 2, ReadyReplicas: 2, AvailableReplicas: 2},
+			},
+			expected: componentStatus{ready: true, message: "2/2 ready"},
+		},
+		{
+			description: "an old replica is terminating",
+			deployment: appsv1.Deployment{
+				Spec:   appsv1.DeploymentSpec{Replicas: &replicas},
+				Status: appsv1.DeploymentStatus{Replicas: 3, UpdatedReplicas: 2, ReadyReplicas: 2, AvailableReplicas: 2},
+			},
+			expected: componentStatus{ready: false, message: "2/2 ready, 1 old replica(s) terminating"},
+		},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, getDeploymentRolloutStatus(test.deployment), test.description)
+	}
+}
+
+func TestGetJobStatus(t *testing.T) {
+	job := batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "backup"}}
+	succeeded, err := getJobStatus(job)
+	assert.False(t, succeeded)
+	assert.Nil(t, err)
+
+	job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Message: "Job has reached the specified backoff limit"}}
+	succeeded, err = getJobStatus(job)
+	assert.False(t, succeeded)
+	assert.EqualError(t, err, "job/backup failed: Job has reached the specified backoff limit")
+
+	job.Status = batchv1.JobStatus{Succeeded: 1}
+	succeeded, err = getJobStatus(job)
+	assert.True(t, succeeded)
+	assert.Nil(t, err)
+}
+
+func TestGetPodStatus(t *testing.T) {
+	pod := corev1.Pod{
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "webserver"}, {Name: "sidecar"}}},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodPending,
+			ContainerStatuses: []corev1.ContainerStatus{
+				{Name: "webserver", State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ImagePullBackOff"}}},
+				{Name: "sidecar", Ready: true},
+			},
+		},
+	}
+	assert.Equal(t, componentStatus{ready: false, message: "ImagePullBackOff, 1/2 containers ready"}, getPodStatus(pod))
+
+	pod.Status.Phase = corev1.PodRunning
+	pod.Status.ContainerStatuses[0] = corev1.ContainerStatus{Name: "webserver", Ready: true}
+	pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}
+	assert.Equal(t, componentStatus{ready: true, message: "Running, 2/2 containers ready"}, getPodStatus(pod))
+}
+
+func TestFormatEvents(t *testing.T) {
+	now := time.Now()
+	events := []corev1.Event{
+		{Type: "Warning", Reason: "BackOff", Message: "Back-off restarting failed container\n", LastTimestamp: metav1.NewTime(now)},
+		{Type: "Normal", Reason: "Pulled", Message: "Container image pulled", LastTimestamp: metav1.NewTime(now.Add(-time.Minute))},
+	}
+	assert.Equal(t, "\n  Normal Pulled: Container image pulled\n  Warning BackOff: Back-off restarting failed container", formatEvents(events))
+	assert.Equal(t, "\n  no events", formatEvents(nil))
+}